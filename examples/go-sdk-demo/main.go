@@ -99,54 +99,36 @@ func (d *GoSDKDemo) demoBasicOperations(ctx context.Context) error {
 	// Add memories
 	fmt.Println("Adding memories...")
 
-	memoryType1 := agentmem.MemoryTypeSemantic
-	importance1 := 0.8
-	memoryID1, err := d.client.AddMemory(ctx, agentmem.CreateMemoryParams{
-		Content:    "The user prefers dark mode in the application",
-		AgentID:    d.demoAgentID,
-		MemoryType: &memoryType1,
-		Importance: &importance1,
-		Metadata: map[string]interface{}{
-			"category":  "user_preferences",
-			"ui_theme":  "dark",
-		},
-	})
+	memoryID1, err := d.client.AddMemory(ctx, agentmem.NewMemory("The user prefers dark mode in the application", d.demoAgentID).
+		WithType(agentmem.MemoryTypeSemantic).
+		WithImportance(0.8).
+		WithMetadata("category", "user_preferences").
+		WithMetadata("ui_theme", "dark").
+		Build())
 	if err != nil {
 		return fmt.Errorf("failed to add semantic memory: %w", err)
 	}
 	d.createdMemoryIDs = append(d.createdMemoryIDs, memoryID1)
 	fmt.Printf("✓ Added semantic memory: %s\n", memoryID1)
 
-	memoryType2 := agentmem.MemoryTypeEpisodic
-	importance2 := 0.6
-	memoryID2, err := d.client.AddMemory(ctx, agentmem.CreateMemoryParams{
-		Content:    "User clicked the 'Export Data' button at 2024-01-15 14:30:00",
-		AgentID:    d.demoAgentID,
-		MemoryType: &memoryType2,
-		Importance: &importance2,
-		Metadata: map[string]interface{}{
-			"action":    "export_data",
-			"timestamp": "2024-01-15T14:30:00Z",
-		},
-	})
+	memoryID2, err := d.client.AddMemory(ctx, agentmem.NewMemory("User clicked the 'Export Data' button at 2024-01-15 14:30:00", d.demoAgentID).
+		WithType(agentmem.MemoryTypeEpisodic).
+		WithImportance(0.6).
+		WithMetadata("action", "export_data").
+		WithMetadata("timestamp", "2024-01-15T14:30:00Z").
+		Build())
 	if err != nil {
 		return fmt.Errorf("failed to add episodic memory: %w", err)
 	}
 	d.createdMemoryIDs = append(d.createdMemoryIDs, memoryID2)
 	fmt.Printf("✓ Added episodic memory: %s\n", memoryID2)
 
-	memoryType3 := agentmem.MemoryTypeProcedural
-	importance3 := 0.9
-	memoryID3, err := d.client.AddMemory(ctx, agentmem.CreateMemoryParams{
-		Content:    "To export data: 1) Go to Settings, 2) Click Export, 3) Choose format",
-		AgentID:    d.demoAgentID,
-		MemoryType: &memoryType3,
-		Importance: &importance3,
-		Metadata: map[string]interface{}{
-			"procedure": "data_export",
-			"steps":     3,
-		},
-	})
+	memoryID3, err := d.client.AddMemory(ctx, agentmem.NewMemory("To export data: 1) Go to Settings, 2) Click Export, 3) Choose format", d.demoAgentID).
+		WithType(agentmem.MemoryTypeProcedural).
+		WithImportance(0.9).
+		WithMetadata("procedure", "data_export").
+		WithMetadata("steps", 3).
+		Build())
 	if err != nil {
 		return fmt.Errorf("failed to add procedural memory: %w", err)
 	}
@@ -189,12 +171,10 @@ func (d *GoSDKDemo) demoAdvancedSearch(ctx context.Context) error {
 
 	// Text search
 	fmt.Println("Searching for 'user preferences'...")
-	textQuery := "user preferences"
-	results1, err := d.client.SearchMemories(ctx, agentmem.SearchQuery{
-		AgentID:   d.demoAgentID,
-		TextQuery: &textQuery,
-		Limit:     5,
-	})
+	results1, err := d.client.SearchMemories(ctx, agentmem.NewSearchBuilder(d.demoAgentID).
+		Text("user preferences").
+		Limit(5).
+		Build())
 	if err != nil {
 		return fmt.Errorf("failed to search memories: %w", err)
 	}
@@ -210,16 +190,12 @@ func (d *GoSDKDemo) demoAdvancedSearch(ctx context.Context) error {
 
 	// Search with filters
 	fmt.Println("\nSearching semantic memories with high importance...")
-	textQuery2 := "preferences"
-	memoryType := agentmem.MemoryTypeSemantic
-	minImportance := 0.7
-	results2, err := d.client.SearchMemories(ctx, agentmem.SearchQuery{
-		AgentID:       d.demoAgentID,
-		TextQuery:     &textQuery2,
-		MemoryType:    &memoryType,
-		MinImportance: &minImportance,
-		Limit:         3,
-	})
+	results2, err := d.client.SearchMemories(ctx, agentmem.NewSearchBuilder(d.demoAgentID).
+		Text("preferences").
+		OfType(agentmem.MemoryTypeSemantic).
+		MinImportance(0.7).
+		Limit(3).
+		Build())
 	if err != nil {
 		return fmt.Errorf("failed to search with filters: %w", err)
 	}
@@ -228,13 +204,10 @@ func (d *GoSDKDemo) demoAdvancedSearch(ctx context.Context) error {
 
 	// Metadata search
 	fmt.Println("\nSearching by metadata...")
-	results3, err := d.client.SearchMemories(ctx, agentmem.SearchQuery{
-		AgentID: d.demoAgentID,
-		MetadataFilters: map[string]interface{}{
-			"category": "user_preferences",
-		},
-		Limit: 5,
-	})
+	results3, err := d.client.SearchMemories(ctx, agentmem.NewSearchBuilder(d.demoAgentID).
+		Metadata("category", "user_preferences").
+		Limit(5).
+		Build())
 	if err != nil {
 		return fmt.Errorf("failed to search by metadata: %w", err)
 	}
@@ -251,43 +224,25 @@ func (d *GoSDKDemo) demoBatchOperations(ctx context.Context) error {
 	// Batch add memories
 	fmt.Println("Adding memories in batch...")
 
-	semanticType := agentmem.MemoryTypeSemantic
-	episodicType := agentmem.MemoryTypeEpisodic
-	importance1 := 0.7
-	importance2 := 0.6
-	importance3 := 0.5
-
 	batchMemories := []agentmem.CreateMemoryParams{
-		{
-			Content:    "User's favorite programming language is Go",
-			AgentID:    d.demoAgentID,
-			MemoryType: &semanticType,
-			Importance: &importance1,
-			Metadata: map[string]interface{}{
-				"category": "preferences",
-				"topic":    "programming",
-			},
-		},
-		{
-			Content:    "User completed Go tutorial on 2024-01-10",
-			AgentID:    d.demoAgentID,
-			MemoryType: &episodicType,
-			Importance: &importance2,
-			Metadata: map[string]interface{}{
-				"achievement": "tutorial_completion",
-				"language":    "go",
-			},
-		},
-		{
-			Content:    "User asked about Go concurrency patterns",
-			AgentID:    d.demoAgentID,
-			MemoryType: &episodicType,
-			Importance: &importance3,
-			Metadata: map[string]interface{}{
-				"topic":    "concurrency",
-				"question": true,
-			},
-		},
+		agentmem.NewMemory("User's favorite programming language is Go", d.demoAgentID).
+			WithType(agentmem.MemoryTypeSemantic).
+			WithImportance(0.7).
+			WithMetadata("category", "preferences").
+			WithMetadata("topic", "programming").
+			Build(),
+		agentmem.NewMemory("User completed Go tutorial on 2024-01-10", d.demoAgentID).
+			WithType(agentmem.MemoryTypeEpisodic).
+			WithImportance(0.6).
+			WithMetadata("achievement", "tutorial_completion").
+			WithMetadata("language", "go").
+			Build(),
+		agentmem.NewMemory("User asked about Go concurrency patterns", d.demoAgentID).
+			WithType(agentmem.MemoryTypeEpisodic).
+			WithImportance(0.5).
+			WithMetadata("topic", "concurrency").
+			WithMetadata("question", true).
+			Build(),
 	}
 
 	batchIDs, err := d.client.BatchAddMemories(ctx, agentmem.BatchCreateMemoryParams{
@@ -389,16 +344,16 @@ func (d *GoSDKDemo) demoErrorHandling(ctx context.Context) error {
 func (d *GoSDKDemo) cleanup(ctx context.Context) {
 	fmt.Println("\n🧹 Cleaning up demo data...")
 
-	cleanupCount := 0
-	for _, memoryID := range d.createdMemoryIDs {
-		if err := d.client.DeleteMemory(ctx, memoryID); err != nil {
-			fmt.Printf("⚠️  Failed to delete %s: %v\n", memoryID, err)
-		} else {
-			cleanupCount++
-		}
+	result, err := d.client.BatchDeleteMemories(ctx, d.createdMemoryIDs)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to delete demo memories: %v\n", err)
+		return
+	}
+	for memoryID, err := range result.Failed {
+		fmt.Printf("⚠️  Failed to delete %s: %v\n", memoryID, err)
 	}
 
-	fmt.Printf("✓ Cleaned up %d memories\n", cleanupCount)
+	fmt.Printf("✓ Cleaned up %d memories\n", len(result.Deleted))
 }
 
 func main() {
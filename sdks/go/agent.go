@@ -0,0 +1,80 @@
+package agentmem
+
+import "context"
+
+// AgentClient is a Client scoped to one agent. Its methods that take
+// memories or queries fill an empty AgentID from the scope; an AgentID that
+// is already set is kept. All other Client methods are available unchanged.
+type AgentClient struct {
+	*Client
+	agentID string
+}
+
+// ForAgent returns a client scoped to agentID, sharing c's configuration,
+// connections, and cache
+//
+//	agent := client.ForAgent("agent_123")
+//	id, err := agent.AddMemory(ctx, agentmem.CreateMemoryParams{Content: "..."})
+func (c *Client) ForAgent(agentID string) *AgentClient {
+	return &AgentClient{Client: c, agentID: agentID}
+}
+
+// AgentID returns the agent the client is scoped to
+func (a *AgentClient) AgentID() string {
+	return a.agentID
+}
+
+// AddMemory adds a new memory, for the scoped agent unless params sets one
+func (a *AgentClient) AddMemory(ctx context.Context, params CreateMemoryParams) (string, error) {
+	return a.Client.AddMemory(ctx, a.createParams(params))
+}
+
+// BatchAddMemories adds multiple memories, each for the scoped agent
+// unless it sets one
+func (a *AgentClient) BatchAddMemories(ctx context.Context, params BatchCreateMemoryParams) ([]string, error) {
+	memories := make([]CreateMemoryParams, len(params.Memories))
+	for i, memory := range params.Memories {
+		memories[i] = a.createParams(memory)
+	}
+	params.Memories = memories
+	return a.Client.BatchAddMemories(ctx, params)
+}
+
+// SearchMemories searches the scoped agent's memories, unless query sets
+// AgentID or AgentIDs
+func (a *AgentClient) SearchMemories(ctx context.Context, query SearchQuery) ([]SearchResult, error) {
+	return a.Client.SearchMemories(ctx, a.query(query))
+}
+
+// SearchMemoriesWithOptions searches the scoped agent's memories with
+// per-request options, unless query sets AgentID or AgentIDs
+func (a *AgentClient) SearchMemoriesWithOptions(ctx context.Context, query SearchQuery, opts RequestOptions) ([]SearchResult, error) {
+	return a.Client.SearchMemoriesWithOptions(ctx, a.query(query), opts)
+}
+
+// CountMemories counts the scoped agent's memories matching the filters of
+// query, unless query sets AgentID
+func (a *AgentClient) CountMemories(ctx context.Context, query SearchQuery) (int, error) {
+	return a.Client.CountMemories(ctx, a.query(query))
+}
+
+// GetMemoryStats retrieves the memory statistics of the scoped agent
+func (a *AgentClient) GetMemoryStats(ctx context.Context) (*MemoryStats, error) {
+	return a.Client.GetMemoryStats(ctx, a.agentID)
+}
+
+// createParams fills the agent of params from the scope
+func (a *AgentClient) createParams(params CreateMemoryParams) CreateMemoryParams {
+	if params.AgentID == "" {
+		params.AgentID = a.agentID
+	}
+	return params
+}
+
+// query fills the agent of a query from the scope
+func (a *AgentClient) query(query SearchQuery) SearchQuery {
+	if query.AgentID == "" && len(query.AgentIDs) == 0 {
+		query.AgentID = a.agentID
+	}
+	return query
+}
@@ -0,0 +1,264 @@
+package agentmem
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// StreamAgentStats fetches memory statistics for many agents using up to
+// concurrency parallel requests. fn is called once per agent as results
+// arrive, with either the stats or the error for that agent; calls to fn are
+// serialized, so it needs no locking of its own. When ctx is cancelled no
+// further requests are started and ctx's error is returned once in-flight
+// requests have finished.
+func (c *Client) StreamAgentStats(ctx context.Context, agentIDs []string, concurrency int, fn func(agentID string, stats *MemoryStats, err error)) error {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	jobs := make(chan string)
+	var (
+		wg     sync.WaitGroup
+		fnLock sync.Mutex
+	)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for agentID := range jobs {
+				stats, err := c.GetMemoryStats(ctx, agentID)
+				fnLock.Lock()
+				fn(agentID, stats, err)
+				fnLock.Unlock()
+			}
+		}()
+	}
+
+dispatch:
+	for _, agentID := range agentIDs {
+		select {
+		case jobs <- agentID:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+// concurrentAddChunkSize is the number of memories per request sent by
+// AddMemoriesConcurrent
+const concurrentAddChunkSize = 100
+
+// addChunk is a run of memories sent in one BatchAddMemories request, with
+// the index of each in the caller's slice
+type addChunk struct {
+	indexes  []int
+	memories []CreateMemoryParams
+}
+
+// AddMemoriesConcurrent creates many memories by splitting them into chunks
+// sent as BatchAddMemories requests, using up to concurrency parallel
+// requests. The returned IDs are in the order of params. If some memories
+// are not created, their IDs are empty and a *BatchAddError maps each of
+// their indexes to its error; invalid memories are reported there without
+// failing the rest of their chunk. When ctx is cancelled no further chunks
+// are sent, and their memories fail with a CancelledError.
+func (c *Client) AddMemoriesConcurrent(ctx context.Context, params []CreateMemoryParams, concurrency int) ([]string, error) {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	ids := make([]string, len(params))
+	failed := c.ValidateBatch(BatchCreateMemoryParams{Memories: params}).Failed
+	var chunks []addChunk
+	var chunk addChunk
+	for i, memory := range params {
+		if _, invalid := failed[i]; invalid {
+			continue
+		}
+		chunk.indexes = append(chunk.indexes, i)
+		chunk.memories = append(chunk.memories, memory)
+		if len(chunk.memories) == concurrentAddChunkSize {
+			chunks = append(chunks, chunk)
+			chunk = addChunk{}
+		}
+	}
+	if len(chunk.memories) > 0 {
+		chunks = append(chunks, chunk)
+	}
+
+	jobs := make(chan addChunk)
+	var (
+		wg       sync.WaitGroup
+		failLock sync.Mutex
+	)
+	fail := func(indexes []int, err error) {
+		failLock.Lock()
+		defer failLock.Unlock()
+		for _, i := range indexes {
+			failed[i] = err
+		}
+	}
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range jobs {
+				created, err := c.BatchAddMemories(ctx, BatchCreateMemoryParams{Memories: chunk.memories})
+				if err == nil && len(created) != len(chunk.memories) {
+					err = NewDecodeError(200, fmt.Errorf("expected %d memory IDs, got %d", len(chunk.memories), len(created)))
+				}
+				if err != nil {
+					fail(chunk.indexes, err)
+					continue
+				}
+				// Each chunk writes a disjoint set of indexes
+				for j, i := range chunk.indexes {
+					ids[i] = created[j]
+				}
+			}
+		}()
+	}
+
+	sent := 0
+dispatch:
+	for _, chunk := range chunks {
+		select {
+		case jobs <- chunk:
+			sent++
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, chunk := range chunks[sent:] {
+		fail(chunk.indexes, NewCancelledError(ctx.Err()))
+	}
+	if len(failed) > 0 {
+		return ids, NewBatchAddError(failed, len(params))
+	}
+	return ids, nil
+}
+
+// NewCreateOp returns a batch operation that creates a memory
+func NewCreateOp(params CreateMemoryParams) BatchOp {
+	return BatchOp{Type: BatchOpCreate, Create: &params}
+}
+
+// NewUpdateOp returns a batch operation that updates a memory
+func NewUpdateOp(memoryID string, params UpdateMemoryParams) BatchOp {
+	return BatchOp{Type: BatchOpUpdate, MemoryID: memoryID, Update: &params}
+}
+
+// NewDeleteOp returns a batch operation that deletes a memory
+func NewDeleteOp(memoryID string) BatchOp {
+	return BatchOp{Type: BatchOpDelete, MemoryID: memoryID}
+}
+
+// NewLinkOp returns a batch operation that links two memories
+func NewLinkOp(sourceID, targetID, relationType string) BatchOp {
+	return BatchOp{
+		Type:     BatchOpLink,
+		MemoryID: sourceID,
+		Link:     &LinkParams{TargetID: targetID, RelationType: relationType},
+	}
+}
+
+// ExecuteBatch applies a mixed batch of create, update, delete, and link
+// operations in one request and returns a result per operation, in order.
+// When atomic is true the server applies all operations or none of them.
+// Create operations without an importance are scored or given a default
+// importance as with AddMemory.
+func (c *Client) ExecuteBatch(ctx context.Context, ops []BatchOp, atomic bool) ([]BatchOpResult, error) {
+	operations := make([]BatchOp, len(ops))
+	for i, op := range ops {
+		prepared, err := c.prepareBatchOp(op)
+		if err != nil {
+			return nil, NewValidationError(fmt.Sprintf("operations[%d]: %s", i, validationMessage(err)))
+		}
+		operations[i] = prepared
+	}
+	// Score only once every operation is known to be valid
+	for i, op := range operations {
+		if op.Type != BatchOpCreate {
+			continue
+		}
+		params, err := c.fillImportance(ctx, *op.Create)
+		if err != nil {
+			return nil, err
+		}
+		operations[i].Create = &params
+	}
+
+	request := BatchRequest{
+		Operations: operations,
+		Atomic:     atomic,
+	}
+	var response BatchResponse
+	err := c.makeRequest(ctx, "POST", "/batch", request, &response, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Results) != len(ops) {
+		return nil, NewDecodeError(200, fmt.Errorf("expected %d batch results, got %d", len(ops), len(response.Results)))
+	}
+	for i, op := range operations {
+		if !response.Results[i].Success {
+			continue
+		}
+		switch op.Type {
+		case BatchOpCreate:
+			c.invalidateAgentAggregates(op.Create.AgentID)
+		case BatchOpUpdate, BatchOpDelete:
+			c.invalidateMemory(op.MemoryID, "")
+		case BatchOpLink:
+			c.invalidateRelations(op.MemoryID, op.Link.TargetID)
+		}
+	}
+	return response.Results, nil
+}
+
+// prepareBatchOp validates a batch operation and applies the same
+// client-side policies as the corresponding single-operation method, except
+// for filling in the importance of a create, which needs a context and is
+// left to ExecuteBatch
+func (c *Client) prepareBatchOp(op BatchOp) (BatchOp, error) {
+	switch op.Type {
+	case BatchOpCreate:
+		if op.Create == nil {
+			return op, NewValidationError("create operation requires create params")
+		}
+		params, err := c.prepareCreateParams(*op.Create)
+		if err != nil {
+			return op, err
+		}
+		op.Create = &params
+	case BatchOpUpdate:
+		if op.MemoryID == "" || op.Update == nil {
+			return op, NewValidationError("update operation requires a memory ID and update params")
+		}
+		if err := op.Update.Validate(); err != nil {
+			return op, err
+		}
+	case BatchOpDelete:
+		if op.MemoryID == "" {
+			return op, NewValidationError("delete operation requires a memory ID")
+		}
+	case BatchOpLink:
+		if op.Link == nil {
+			return op, NewValidationError("link operation requires link params")
+		}
+		if err := validateLink(op.MemoryID, op.Link.TargetID, op.Link.RelationType); err != nil {
+			return op, err
+		}
+	default:
+		return op, NewValidationError(fmt.Sprintf("unknown operation type %q", op.Type))
+	}
+	return op, nil
+}
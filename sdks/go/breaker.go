@@ -0,0 +1,104 @@
+package agentmem
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// circuitBreaker stops requests to a backend that keeps failing. After
+// threshold consecutive failures it opens for the cooldown, then lets a
+// single trial request through; the trial's outcome closes or reopens it.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time // zero while closed
+	trial    bool      // a half-open trial request is in flight
+}
+
+// newCircuitBreaker returns a circuit breaker, or nil when threshold is
+// zero and the breaker is disabled
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	if threshold <= 0 {
+		return nil
+	}
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown}
+}
+
+// allow reports whether a request may be sent. Every allowed request must
+// be followed by a call to record with its outcome.
+func (b *circuitBreaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openedAt.IsZero() {
+		return nil
+	}
+	if wait := b.cooldown - time.Since(b.openedAt); wait > 0 {
+		return NewCircuitOpenError(wait)
+	}
+	if b.trial {
+		return NewCircuitOpenError(0)
+	}
+	b.trial = true
+	return nil
+}
+
+// record feeds the outcome of an allowed request into the breaker. Only
+// failures that indicate an unavailable backend count; cancellation by the
+// caller is neutral.
+func (b *circuitBreaker) record(err error) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	trial := b.trial
+	b.trial = false
+
+	var cancelledErr *CancelledError
+	if errors.As(err, &cancelledErr) {
+		return
+	}
+
+	var networkErr *NetworkError
+	var serverErr *ServerError
+	if errors.As(err, &networkErr) || errors.As(err, &serverErr) {
+		b.failures++
+		if trial || b.failures >= b.threshold {
+			b.openedAt = time.Now()
+		}
+		return
+	}
+
+	b.failures = 0
+	b.openedAt = time.Time{}
+}
+
+// CircuitOpenError is returned without sending the request while the
+// circuit breaker is open after repeated server or network failures
+type CircuitOpenError struct {
+	*AgentMemError
+	// RetryAfter is the remaining cooldown before a trial request is allowed
+	RetryAfter time.Duration
+}
+
+// NewCircuitOpenError creates a new circuit open error
+func NewCircuitOpenError(retryAfter time.Duration) *CircuitOpenError {
+	return &CircuitOpenError{
+		AgentMemError: &AgentMemError{
+			Message:    fmt.Sprintf("Circuit breaker is open after repeated failures; retry in %s", retryAfter.Round(time.Millisecond)),
+			StatusCode: 0,
+			Code:       "CIRCUIT_OPEN_ERROR",
+		},
+		RetryAfter: retryAfter,
+	}
+}
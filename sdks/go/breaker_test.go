@@ -0,0 +1,44 @@
+package agentmem
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestUnsupportedMethodLeavesBreakerAlone(t *testing.T) {
+	var requests int32
+	client := newHandlerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		writeServerError(w, http.StatusInternalServerError, "failing")
+	}), func(config *Config) {
+		config.MaxRetries = 0
+		config.CircuitBreakerThreshold = 2
+		config.CircuitBreakerCooldown = time.Minute
+	})
+	ctx := context.Background()
+
+	var serverErr *ServerError
+	if err := client.makeRequest(ctx, "GET", "/memories/stats", nil, &MemoryStats{}, false); !errors.As(err, &serverErr) {
+		t.Fatalf("first request: error = %v, want *ServerError", err)
+	}
+	if err := client.makeRequest(ctx, "PATCH", "/memories/m1", nil, nil, false); err == nil {
+		t.Fatal("PATCH succeeded, want an unsupported method error")
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("server saw %d requests, want 1", got)
+	}
+
+	// The unsupported method must not have reset the failure count, so this
+	// second failure opens the breaker
+	if err := client.makeRequest(ctx, "GET", "/memories/stats", nil, &MemoryStats{}, false); !errors.As(err, &serverErr) {
+		t.Fatalf("second request: error = %v, want *ServerError", err)
+	}
+	var openErr *CircuitOpenError
+	if err := client.makeRequest(ctx, "GET", "/memories/stats", nil, &MemoryStats{}, false); !errors.As(err, &openErr) {
+		t.Errorf("third request: error = %v, want *CircuitOpenError", err)
+	}
+}
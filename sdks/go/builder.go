@@ -0,0 +1,235 @@
+package agentmem
+
+import (
+	"time"
+)
+
+// SearchQueryBuilder composes a SearchQuery without taking the address of
+// locals for its optional fields.
+//
+//	query := agentmem.NewSearchBuilder(agentID).
+//		Text("preferences").
+//		OfType(agentmem.MemoryTypeSemantic).
+//		MinImportance(0.7).
+//		Limit(5).
+//		Build()
+type SearchQueryBuilder struct {
+	query SearchQuery
+}
+
+// NewSearchBuilder creates a search builder for an agent's memories
+func NewSearchBuilder(agentID string) *SearchQueryBuilder {
+	return &SearchQueryBuilder{query: SearchQuery{AgentID: agentID}}
+}
+
+// ForAgents searches the memories of all the given agents instead of the
+// agent the builder was created for
+func (b *SearchQueryBuilder) ForAgents(agentIDs ...string) *SearchQueryBuilder {
+	b.query.AgentID = ""
+	b.query.AgentIDs = append(b.query.AgentIDs, agentIDs...)
+	return b
+}
+
+// Text sets the text to search for
+func (b *SearchQueryBuilder) Text(text string) *SearchQueryBuilder {
+	b.query.TextQuery = &text
+	return b
+}
+
+// Vector sets the embedding to search by similarity
+func (b *SearchQueryBuilder) Vector(vector []float64) *SearchQueryBuilder {
+	b.query.VectorQuery = append([]float64(nil), vector...)
+	return b
+}
+
+// HybridWeight sets how text and vector scores are fused when searching by
+// both, from 0 (text only) to 1 (vector only)
+func (b *SearchQueryBuilder) HybridWeight(weight float64) *SearchQueryBuilder {
+	b.query.HybridWeight = &weight
+	return b
+}
+
+// OfType restricts results to one memory type
+func (b *SearchQueryBuilder) OfType(memoryType MemoryType) *SearchQueryBuilder {
+	b.query.MemoryType = &memoryType
+	return b
+}
+
+// ForUser restricts results to one user's memories
+func (b *SearchQueryBuilder) ForUser(userID string) *SearchQueryBuilder {
+	b.query.UserID = &userID
+	return b
+}
+
+// MinImportance excludes memories less important than minImportance
+func (b *SearchQueryBuilder) MinImportance(minImportance float64) *SearchQueryBuilder {
+	b.query.MinImportance = &minImportance
+	return b
+}
+
+// MaxAge excludes memories older than maxAge, at second granularity
+func (b *SearchQueryBuilder) MaxAge(maxAge time.Duration) *SearchQueryBuilder {
+	seconds := int(maxAge / time.Second)
+	b.query.MaxAgeSeconds = &seconds
+	return b
+}
+
+// CreatedBetween restricts results to memories created within [after, before].
+// A zero time leaves that side of the window open.
+func (b *SearchQueryBuilder) CreatedBetween(after, before time.Time) *SearchQueryBuilder {
+	b.query.CreatedAfter, b.query.CreatedBefore = timeBound(after), timeBound(before)
+	return b
+}
+
+// UpdatedBetween restricts results to memories updated within [after, before].
+// A zero time leaves that side of the window open.
+func (b *SearchQueryBuilder) UpdatedBetween(after, before time.Time) *SearchQueryBuilder {
+	b.query.UpdatedAfter, b.query.UpdatedBefore = timeBound(after), timeBound(before)
+	return b
+}
+
+// Limit sets the maximum number of results
+func (b *SearchQueryBuilder) Limit(limit int) *SearchQueryBuilder {
+	b.query.Limit = limit
+	return b
+}
+
+// Metadata restricts results to memories whose metadata key equals value.
+// It may be called repeatedly to match several keys.
+func (b *SearchQueryBuilder) Metadata(key string, value interface{}) *SearchQueryBuilder {
+	if b.query.MetadataFilters == nil {
+		b.query.MetadataFilters = make(map[string]interface{})
+	}
+	b.query.MetadataFilters[key] = value
+	return b
+}
+
+// Tags restricts results to memories tagged with any or all of tags,
+// depending on mode
+func (b *SearchQueryBuilder) Tags(mode TagMatchMode, tags ...string) *SearchQueryBuilder {
+	b.query.Tags = append(b.query.Tags, tags...)
+	b.query.TagMatchMode = mode
+	return b
+}
+
+// Filters adds metadata filters, e.g. from a MetadataFilterBuilder
+func (b *SearchQueryBuilder) Filters(filters ...MetadataFilter) *SearchQueryBuilder {
+	b.query.AdvancedFilters = append(b.query.AdvancedFilters, filters...)
+	return b
+}
+
+// SortBy orders the results by field in the given order instead of by score
+func (b *SearchQueryBuilder) SortBy(field SortField, order SortOrder) *SearchQueryBuilder {
+	b.query.SortBy = field
+	b.query.SortOrder = order
+	return b
+}
+
+// IncludeEmbeddings returns the embedding vector of each result
+func (b *SearchQueryBuilder) IncludeEmbeddings() *SearchQueryBuilder {
+	b.query.IncludeEmbeddings = true
+	return b
+}
+
+// Highlight returns the fragments of each result's content matching the
+// text query
+func (b *SearchQueryBuilder) Highlight() *SearchQueryBuilder {
+	b.query.Highlight = true
+	return b
+}
+
+// Build returns the composed query. The builder may be reused afterwards
+// without affecting queries already built.
+func (b *SearchQueryBuilder) Build() SearchQuery {
+	return b.query.Clone()
+}
+
+// MemoryBuilder composes CreateMemoryParams without taking the address of
+// locals for its optional fields. Fields that are not set stay nil, so the
+// server applies its defaults.
+//
+//	params := agentmem.NewMemory("The user prefers dark mode", agentID).
+//		WithType(agentmem.MemoryTypeSemantic).
+//		WithImportance(0.8).
+//		WithMetadata("category", "user_preferences").
+//		Build()
+type MemoryBuilder struct {
+	params CreateMemoryParams
+}
+
+// NewMemory creates a memory builder for content owned by an agent
+func NewMemory(content, agentID string) *MemoryBuilder {
+	return &MemoryBuilder{params: CreateMemoryParams{Content: content, AgentID: agentID}}
+}
+
+// WithType sets the memory type
+func (b *MemoryBuilder) WithType(memoryType MemoryType) *MemoryBuilder {
+	b.params.MemoryType = &memoryType
+	return b
+}
+
+// WithImportance sets the importance score
+func (b *MemoryBuilder) WithImportance(importance float64) *MemoryBuilder {
+	b.params.Importance = &importance
+	b.params.ImportanceLevel = 0
+	return b
+}
+
+// WithImportanceLevel sets the importance as a level instead of a float,
+// replacing any importance set with WithImportance
+func (b *MemoryBuilder) WithImportanceLevel(level ImportanceLevel) *MemoryBuilder {
+	b.params.Importance = nil
+	b.params.ImportanceLevel = level
+	return b
+}
+
+// WithUserID sets the user the memory belongs to
+func (b *MemoryBuilder) WithUserID(userID string) *MemoryBuilder {
+	b.params.UserID = &userID
+	return b
+}
+
+// WithSession sets the session the memory was recorded in
+func (b *MemoryBuilder) WithSession(sessionID string) *MemoryBuilder {
+	b.params.SessionID = &sessionID
+	return b
+}
+
+// WithMetadata sets a metadata key. It may be called repeatedly to set
+// several keys.
+func (b *MemoryBuilder) WithMetadata(key string, value interface{}) *MemoryBuilder {
+	if b.params.Metadata == nil {
+		b.params.Metadata = make(map[string]interface{})
+	}
+	b.params.Metadata[key] = value
+	return b
+}
+
+// WithTags adds tags. It may be called repeatedly to add more.
+func (b *MemoryBuilder) WithTags(tags ...string) *MemoryBuilder {
+	b.params.Tags = append(b.params.Tags, tags...)
+	return b
+}
+
+// Build returns the composed params. The builder may be reused afterwards
+// without affecting params already built.
+func (b *MemoryBuilder) Build() CreateMemoryParams {
+	params := b.params
+	params.MemoryType = clonePtr(b.params.MemoryType)
+	params.UserID = clonePtr(b.params.UserID)
+	params.SessionID = clonePtr(b.params.SessionID)
+	params.Importance = clonePtr(b.params.Importance)
+	params.Metadata = cloneMetadata(b.params.Metadata)
+	if b.params.Tags != nil {
+		params.Tags = append([]string(nil), b.params.Tags...)
+	}
+	return params
+}
+
+// timeBound returns a pointer to t, or nil for the zero time
+func timeBound(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
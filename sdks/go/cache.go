@@ -0,0 +1,350 @@
+package agentmem
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Cache is a pluggable backend for the client's response cache, set through
+// Config.Cache, e.g. to share cached responses across processes. Values are
+// the decoded API responses; a backend that serializes them may return any
+// value that encodes to the same JSON, such as a json.RawMessage.
+// Implementations must be safe for concurrent use and should drop entries
+// once their ttl has elapsed.
+type Cache interface {
+	// Get returns the value stored for key, if present and not expired
+	Get(key string) (interface{}, bool)
+	// Set stores value for key for the duration of ttl
+	Set(key string, value interface{}, ttl time.Duration)
+	// Delete removes the value stored for key, if any
+	Delete(key string)
+	// Clear removes all values
+	Clear()
+}
+
+// lruItem is an element stored in an lruCache
+type lruItem struct {
+	key   string
+	entry *cacheEntry
+}
+
+// lruCache is a size-bounded cache that evicts the least-recently-used entry.
+// It is not safe for concurrent use; callers hold the client's cacheMutex.
+type lruCache struct {
+	maxEntries int
+	order      *list.List
+	items      map[string]*list.Element
+}
+
+// newLRUCache creates an LRU cache holding at most maxEntries entries
+func newLRUCache(maxEntries int) *lruCache {
+	return &lruCache{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+// get returns the entry for key and marks it as most recently used
+func (l *lruCache) get(key string) (*cacheEntry, bool) {
+	elem, exists := l.items[key]
+	if !exists {
+		return nil, false
+	}
+	l.order.MoveToFront(elem)
+	return elem.Value.(*lruItem).entry, true
+}
+
+// set stores an entry and returns the keys evicted to make room for it
+func (l *lruCache) set(key string, entry *cacheEntry) []string {
+	if elem, exists := l.items[key]; exists {
+		elem.Value.(*lruItem).entry = entry
+		l.order.MoveToFront(elem)
+		return nil
+	}
+
+	l.items[key] = l.order.PushFront(&lruItem{key: key, entry: entry})
+
+	var evicted []string
+	for l.maxEntries > 0 && l.order.Len() > l.maxEntries {
+		oldest := l.order.Back()
+		item := l.order.Remove(oldest).(*lruItem)
+		delete(l.items, item.key)
+		evicted = append(evicted, item.key)
+	}
+	return evicted
+}
+
+// remove deletes the entry for key if present
+func (l *lruCache) remove(key string) {
+	if elem, exists := l.items[key]; exists {
+		l.order.Remove(elem)
+		delete(l.items, key)
+	}
+}
+
+// len returns the number of entries in the cache
+func (l *lruCache) len() int {
+	return l.order.Len()
+}
+
+// cacheCounters tracks the effectiveness of the client's response cache
+type cacheCounters struct {
+	hits      int64
+	misses    int64
+	evictions int64
+}
+
+// GetCacheStats returns statistics about the client's local response cache.
+// Evictions counts entries dropped to respect MaxCacheEntries or
+// CacheMaxEntriesPerAgent; expired entries are counted as misses when read.
+// With a custom Config.Cache only Hits, Misses, and HitRate are tracked.
+func (c *Client) GetCacheStats() CacheStats {
+	c.cacheMutex.RLock()
+	defer c.cacheMutex.RUnlock()
+
+	entries := c.cache.len()
+	for _, partition := range c.partitions {
+		entries += partition.len()
+	}
+
+	stats := CacheStats{
+		Hits:      atomic.LoadInt64(&c.cacheCounters.hits),
+		Misses:    atomic.LoadInt64(&c.cacheCounters.misses),
+		Evictions: atomic.LoadInt64(&c.cacheCounters.evictions),
+		Entries:   entries,
+	}
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(lookups)
+	}
+	return stats
+}
+
+// getFromPartition retrieves a valid entry from an agent's cache partition.
+// The caller must hold cacheMutex.
+func (c *Client) getFromPartition(agentID, key string) (interface{}, bool) {
+	partition, exists := c.partitions[agentID]
+	if !exists {
+		delete(c.cacheAgents, key)
+		return nil, false
+	}
+
+	entry, exists := partition.get(key)
+	if !exists {
+		delete(c.cacheAgents, key)
+		return nil, false
+	}
+
+	if entry.expired(c.config.CacheTTL) {
+		c.removeFromPartition(agentID, key)
+		return nil, false
+	}
+
+	return entry.data, true
+}
+
+// setInPartition stores an entry in an agent's cache partition, evicting
+// that agent's least-recently-used entries when the partition is full.
+// The caller must hold cacheMutex.
+func (c *Client) setInPartition(agentID, key string, entry *cacheEntry) {
+	// The key may previously have been cached globally or for another agent
+	c.cache.remove(key)
+	if previous, exists := c.cacheAgents[key]; exists && previous != agentID {
+		c.removeFromPartition(previous, key)
+	}
+
+	partition, exists := c.partitions[agentID]
+	if !exists {
+		partition = newLRUCache(c.config.CacheMaxEntriesPerAgent)
+		c.partitions[agentID] = partition
+	}
+
+	c.cacheAgents[key] = agentID
+	for _, evictedKey := range partition.set(key, entry) {
+		delete(c.cacheAgents, evictedKey)
+		atomic.AddInt64(&c.cacheCounters.evictions, 1)
+	}
+}
+
+// removeFromPartition deletes an entry from an agent's cache partition,
+// dropping the partition once it is empty. The caller must hold cacheMutex.
+func (c *Client) removeFromPartition(agentID, key string) {
+	delete(c.cacheAgents, key)
+	partition, exists := c.partitions[agentID]
+	if !exists {
+		return
+	}
+	partition.remove(key)
+	if partition.len() == 0 {
+		delete(c.partitions, agentID)
+	}
+}
+
+// invalidateMemory drops every cached GET entry for a memory, whatever its
+// projection, its cached version history, and the cached stats and counts of
+// the agent owning it, as well as its stored ETag. agentID may be empty, in
+// which case it is taken from a cached copy of the memory; if no copy is
+// cached, the stats and counts of every agent are dropped.
+func (c *Client) invalidateMemory(memoryID, agentID string) {
+	c.etags.remove(memoryID)
+	if !c.config.EnableCaching {
+		return
+	}
+
+	if c.config.Cache != nil {
+		c.invalidateMemoryInCache(c.config.Cache, memoryID, agentID)
+		return
+	}
+
+	c.cacheMutex.Lock()
+	defer c.cacheMutex.Unlock()
+
+	fullKey := c.getCacheKey("GET", fmt.Sprintf("/memories/%s", memoryID), nil)
+	var keys []string
+	for key := range c.cache.items {
+		if isMemoryCacheKey(key, fullKey) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range c.cacheAgents {
+		if isMemoryCacheKey(key, fullKey) {
+			keys = append(keys, key)
+		}
+	}
+
+	for _, key := range keys {
+		if agentID == "" {
+			agentID = c.cachedMemoryAgentID(key)
+		}
+		c.removeCacheKey(key)
+	}
+	c.removeCacheKey(c.memoryHistoryCacheKey(memoryID))
+	c.removeAggregateKeys(agentID)
+}
+
+// invalidateAgentAggregates drops an agent's cached stats and counts, which
+// any write to its memories can change. An empty agentID drops those of
+// every agent. A custom Cache cannot be enumerated, so only the agent's
+// unfiltered stats are dropped there.
+func (c *Client) invalidateAgentAggregates(agentID string) {
+	if !c.config.EnableCaching {
+		return
+	}
+	if c.config.Cache != nil {
+		if agentID != "" {
+			c.config.Cache.Delete(c.getCacheKey("GET", "/memories/stats", map[string]interface{}{"agent_id": agentID}))
+		}
+		return
+	}
+
+	c.cacheMutex.Lock()
+	defer c.cacheMutex.Unlock()
+	c.removeAggregateKeys(agentID)
+}
+
+// removeAggregateKeys deletes the cached stats and counts of an agent, or of
+// every agent when agentID is empty. The caller must hold cacheMutex.
+func (c *Client) removeAggregateKeys(agentID string) {
+	var agentParam string
+	if agentID != "" {
+		encodedID, _ := json.Marshal(agentID)
+		agentParam = `"agent_id":` + string(encodedID)
+	}
+	isAggregate := func(key string) bool {
+		if !strings.HasPrefix(key, "GET:/memories/stats:") && !strings.HasPrefix(key, "GET:/memories/count:") {
+			return false
+		}
+		return agentParam == "" || strings.Contains(key, agentParam)
+	}
+
+	var keys []string
+	for key := range c.cache.items {
+		if isAggregate(key) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range c.cacheAgents {
+		if isAggregate(key) {
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range keys {
+		c.removeCacheKey(key)
+	}
+}
+
+// invalidateMemoryInCache drops the cached entries of a memory from a custom
+// cache. Keys cannot be enumerated there, so each known variant is deleted.
+func (c *Client) invalidateMemoryInCache(cache Cache, memoryID, agentID string) {
+	endpoint := fmt.Sprintf("/memories/%s", memoryID)
+	fullKey := c.getCacheKey("GET", endpoint, nil)
+	if agentID == "" {
+		if cached, found := cache.Get(fullKey); found {
+			var memory Memory
+			if c.decodeCached(cached, &memory) == nil {
+				agentID = memory.AgentID
+			}
+		}
+	}
+
+	cache.Delete(fullKey)
+	cache.Delete(c.memoryCacheKey(memoryID, Projection{ExcludeEmbedding: true}))
+	cache.Delete(c.getCacheKey("GET", endpoint, map[string]interface{}{"include_embedding": true}))
+	cache.Delete(c.getCacheKey("GET", endpoint, map[string]interface{}{"include_relations": true}))
+	cache.Delete(c.memoryHistoryCacheKey(memoryID))
+	if agentID != "" {
+		cache.Delete(c.getCacheKey("GET", "/memories/stats", map[string]interface{}{"agent_id": agentID}))
+	}
+}
+
+// isMemoryCacheKey reports whether key is fullKey or a projected variant of it
+func isMemoryCacheKey(key, fullKey string) bool {
+	return key == fullKey || strings.HasPrefix(key, fullKey+":")
+}
+
+// cachedMemoryAgentID returns the agent ID of a cached memory, if any.
+// The caller must hold cacheMutex.
+func (c *Client) cachedMemoryAgentID(key string) string {
+	if agentID, partitioned := c.cacheAgents[key]; partitioned {
+		return agentID
+	}
+	if elem, exists := c.cache.items[key]; exists {
+		if memory, ok := elem.Value.(*lruItem).entry.data.(*Memory); ok {
+			return memory.AgentID
+		}
+	}
+	return ""
+}
+
+// invalidateCacheKeys drops the given entries from the cache
+func (c *Client) invalidateCacheKeys(keys ...string) {
+	if !c.config.EnableCaching {
+		return
+	}
+	if c.config.Cache != nil {
+		for _, key := range keys {
+			c.config.Cache.Delete(key)
+		}
+		return
+	}
+
+	c.cacheMutex.Lock()
+	defer c.cacheMutex.Unlock()
+	for _, key := range keys {
+		c.removeCacheKey(key)
+	}
+}
+
+// removeCacheKey deletes a cache entry wherever it is stored.
+// The caller must hold cacheMutex.
+func (c *Client) removeCacheKey(key string) {
+	if agentID, partitioned := c.cacheAgents[key]; partitioned {
+		c.removeFromPartition(agentID, key)
+		return
+	}
+	c.cache.remove(key)
+}
@@ -0,0 +1,310 @@
+package agentmem
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// batchGetServer serves /memories/batch/get for memories that all have an
+// embedding, except for the ID "missing", which is not found. It records the
+// IDs requested by each call.
+type batchGetServer struct {
+	mu       sync.Mutex
+	requests []BatchGetMemoryParams
+}
+
+func (s *batchGetServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var params BatchGetMemoryParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		writeServerError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.mu.Lock()
+	s.requests = append(s.requests, params)
+	s.mu.Unlock()
+
+	response := BatchGetResponse{Memories: []Memory{}}
+	for _, id := range params.IDs {
+		if id == "missing" {
+			continue
+		}
+		memory := Memory{ID: id, AgentID: "agent", Content: "content of " + id, Embedding: []float64{1, 2, 3}}
+		if params.ExcludeEmbedding {
+			memory.Embedding = nil
+		}
+		response.Memories = append(response.Memories, memory)
+	}
+	writeServerJSON(w, http.StatusOK, response)
+}
+
+func (s *batchGetServer) requested() []BatchGetMemoryParams {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]BatchGetMemoryParams(nil), s.requests...)
+}
+
+func newBatchGetClient(t *testing.T) (*Client, *batchGetServer) {
+	server := &batchGetServer{}
+	client := newHandlerClient(t, server, func(config *Config) {
+		config.EnableCaching = true
+	})
+	return client, server
+}
+
+func TestGetMemoriesProjectedEntryDoesNotSatisfyFullFetch(t *testing.T) {
+	client, server := newBatchGetClient(t)
+	ctx := context.Background()
+
+	projected, err := client.GetMemories(ctx, []string{"a"}, Projection{ExcludeEmbedding: true})
+	if err != nil {
+		t.Fatalf("projected GetMemories: %v", err)
+	}
+	if projected[0].Embedding != nil {
+		t.Errorf("projected memory has embedding %v", projected[0].Embedding)
+	}
+
+	full, err := client.GetMemories(ctx, []string{"a"}, Projection{})
+	if err != nil {
+		t.Fatalf("full GetMemories: %v", err)
+	}
+	if len(full[0].Embedding) != 3 {
+		t.Errorf("full memory embedding = %v, want the stored vector", full[0].Embedding)
+	}
+
+	want := []BatchGetMemoryParams{
+		{IDs: []string{"a"}, ExcludeEmbedding: true},
+		{IDs: []string{"a"}},
+	}
+	if got := server.requested(); !reflect.DeepEqual(got, want) {
+		t.Errorf("requests = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetMemoriesFullEntrySatisfiesProjectedFetch(t *testing.T) {
+	client, server := newBatchGetClient(t)
+	ctx := context.Background()
+
+	if _, err := client.GetMemories(ctx, []string{"a", "b"}, Projection{}); err != nil {
+		t.Fatalf("full GetMemories: %v", err)
+	}
+
+	projected, err := client.GetMemories(ctx, []string{"a", "c"}, Projection{ExcludeEmbedding: true})
+	if err != nil {
+		t.Fatalf("projected GetMemories: %v", err)
+	}
+	for _, memory := range projected {
+		if memory.Embedding != nil {
+			t.Errorf("projected memory %s has embedding %v", memory.ID, memory.Embedding)
+		}
+	}
+
+	// The projected copy served from the full entry must not strip it
+	full, err := client.GetMemories(ctx, []string{"a"}, Projection{})
+	if err != nil {
+		t.Fatalf("second full GetMemories: %v", err)
+	}
+	if len(full[0].Embedding) != 3 {
+		t.Errorf("cached full memory embedding = %v, want the stored vector", full[0].Embedding)
+	}
+
+	want := []BatchGetMemoryParams{
+		{IDs: []string{"a", "b"}},
+		{IDs: []string{"c"}, ExcludeEmbedding: true},
+	}
+	if got := server.requested(); !reflect.DeepEqual(got, want) {
+		t.Errorf("requests = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetMemoriesPopulatesGetMemoryCache(t *testing.T) {
+	client, server := newBatchGetClient(t)
+	ctx := context.Background()
+
+	if _, err := client.GetMemories(ctx, []string{"a"}, Projection{}); err != nil {
+		t.Fatalf("GetMemories: %v", err)
+	}
+	memory, err := client.GetMemory(ctx, "a")
+	if err != nil {
+		t.Fatalf("GetMemory: %v", err)
+	}
+	if memory.ID != "a" || len(memory.Embedding) != 3 {
+		t.Errorf("GetMemory = %+v, want the cached full record", memory)
+	}
+	if got := len(server.requested()); got != 1 {
+		t.Errorf("server saw %d requests, want 1", got)
+	}
+}
+
+func TestGetMemoriesDuplicateIDs(t *testing.T) {
+	server := &batchGetServer{}
+	logger := &recordingLogger{}
+	client := newHandlerClient(t, server, func(config *Config) {
+		config.EnableLogging = true
+		config.Logger = logger
+	})
+
+	ids := []string{"a", "b", "a", "missing", "a"}
+	memories, err := client.GetMemories(context.Background(), ids, Projection{})
+	if err != nil {
+		t.Fatalf("GetMemories: %v", err)
+	}
+
+	if len(memories) != len(ids) {
+		t.Fatalf("len(memories) = %d, want %d", len(memories), len(ids))
+	}
+	for i, id := range ids {
+		if id == "missing" {
+			if memories[i] != nil {
+				t.Errorf("memories[%d] = %+v, want nil for a missing ID", i, memories[i])
+			}
+			continue
+		}
+		if memories[i] == nil || memories[i].ID != id {
+			t.Errorf("memories[%d] = %+v, want memory %s", i, memories[i], id)
+		}
+	}
+	if memories[0] == memories[2] || memories[2] == memories[4] {
+		t.Error("duplicate positions share one *Memory")
+	}
+	memories[0].Content = "changed"
+	if memories[2].Content == "changed" {
+		t.Error("changing one duplicate changed another")
+	}
+
+	want := []BatchGetMemoryParams{{IDs: []string{"a", "b", "missing"}}}
+	if got := server.requested(); !reflect.DeepEqual(got, want) {
+		t.Errorf("requests = %+v, want each ID once: %+v", got, want)
+	}
+	if output := logger.output(); !strings.Contains(output, "WARN GetMemories called with 2 duplicate memory IDs") {
+		t.Errorf("log output %q has no duplicate warning", output)
+	}
+}
+
+func TestCountMemoriesCachedUntilWrite(t *testing.T) {
+	handler := &countingHandler{handler: NewMemoryServer()}
+	client := newHandlerClient(t, handler, func(config *Config) {
+		config.EnableCaching = true
+	})
+	ctx := context.Background()
+	query := SearchQuery{AgentID: "agent"}
+
+	assertCount := func(want int, wantRequests int64) {
+		t.Helper()
+		count, err := client.CountMemories(ctx, query)
+		if err != nil {
+			t.Fatalf("CountMemories: %v", err)
+		}
+		if count != want {
+			t.Errorf("CountMemories = %d, want %d", count, want)
+		}
+		if got := handler.count("GET", "/memories/count"); got != wantRequests {
+			t.Errorf("server saw %d count requests, want %d", got, wantRequests)
+		}
+	}
+
+	assertCount(0, 1)
+	assertCount(0, 1)
+	id, err := client.AddMemory(ctx, CreateMemoryParams{AgentID: "agent", Content: "first"})
+	if err != nil {
+		t.Fatalf("AddMemory: %v", err)
+	}
+	assertCount(1, 2)
+	assertCount(1, 2)
+	if err := client.DeleteMemory(ctx, id); err != nil {
+		t.Fatalf("DeleteMemory: %v", err)
+	}
+	assertCount(0, 3)
+}
+
+func TestGetMemoryStatsFilteredCachedUntilWrite(t *testing.T) {
+	handler := &countingHandler{handler: NewMemoryServer()}
+	client := newHandlerClient(t, handler, func(config *Config) {
+		config.EnableCaching = true
+	})
+	ctx := context.Background()
+	episodic := MemoryTypeEpisodic
+	query := StatsQuery{AgentID: "agent", MemoryType: &episodic}
+
+	assertTotal := func(want int, wantRequests int64) {
+		t.Helper()
+		stats, err := client.GetMemoryStatsFiltered(ctx, query)
+		if err != nil {
+			t.Fatalf("GetMemoryStatsFiltered: %v", err)
+		}
+		if stats.TotalMemories != want {
+			t.Errorf("TotalMemories = %d, want %d", stats.TotalMemories, want)
+		}
+		if got := handler.count("GET", "/memories/stats"); got != wantRequests {
+			t.Errorf("server saw %d stats requests, want %d", got, wantRequests)
+		}
+	}
+
+	assertTotal(0, 1)
+	assertTotal(0, 1)
+	id, err := client.AddMemory(ctx, CreateMemoryParams{AgentID: "agent", Content: "met Ann", MemoryType: &episodic})
+	if err != nil {
+		t.Fatalf("AddMemory: %v", err)
+	}
+	assertTotal(1, 2)
+	assertTotal(1, 2)
+
+	// A write for another agent leaves the entry cached
+	if _, err := client.AddMemory(ctx, CreateMemoryParams{AgentID: "other", Content: "met Bob", MemoryType: &episodic}); err != nil {
+		t.Fatalf("AddMemory: %v", err)
+	}
+	assertTotal(1, 2)
+
+	if err := client.DeleteMemory(ctx, id); err != nil {
+		t.Fatalf("DeleteMemory: %v", err)
+	}
+	assertTotal(0, 3)
+}
+
+// mapCache is a custom Cache that keeps entries in a map and ignores ttl
+type mapCache struct {
+	entries sync.Map
+}
+
+func (m *mapCache) Get(key string) (interface{}, bool)                 { return m.entries.Load(key) }
+func (m *mapCache) Set(key string, value interface{}, _ time.Duration) { m.entries.Store(key, value) }
+func (m *mapCache) Delete(key string)                                  { m.entries.Delete(key) }
+func (m *mapCache) Clear() {
+	m.entries.Range(func(key, _ interface{}) bool {
+		m.entries.Delete(key)
+		return true
+	})
+}
+
+func TestGetMemoryStatsFilteredCustomCache(t *testing.T) {
+	handler := &countingHandler{handler: NewMemoryServer()}
+	client := newHandlerClient(t, handler, func(config *Config) {
+		config.EnableCaching = true
+		config.Cache = &mapCache{}
+	})
+	ctx := context.Background()
+	episodic := MemoryTypeEpisodic
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetMemoryStatsFiltered(ctx, StatsQuery{AgentID: "agent", MemoryType: &episodic}); err != nil {
+			t.Fatalf("GetMemoryStatsFiltered: %v", err)
+		}
+	}
+	if got := handler.count("GET", "/memories/stats"); got != 2 {
+		t.Errorf("server saw %d filtered stats requests, want 2", got)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetMemoryStatsFiltered(ctx, StatsQuery{AgentID: "agent"}); err != nil {
+			t.Fatalf("GetMemoryStatsFiltered: %v", err)
+		}
+	}
+	if got := handler.count("GET", "/memories/stats"); got != 3 {
+		t.Errorf("server saw %d stats requests in total, want 3", got)
+	}
+}
@@ -3,40 +3,110 @@ package agentmem
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-resty/resty/v2"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
 )
 
 // cacheEntry represents a cached response
 type cacheEntry struct {
 	data      interface{}
 	timestamp time.Time
+	ttl       time.Duration // zero means the configured CacheTTL
+}
+
+// expired reports whether the entry has outlived its TTL
+func (e *cacheEntry) expired(defaultTTL time.Duration) bool {
+	ttl := e.ttl
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+	return time.Since(e.timestamp) > ttl
 }
 
 // Client represents the AgentMem API client
 type Client struct {
-	config     *Config
-	httpClient *resty.Client
-	cache      map[string]*cacheEntry
-	cacheMutex sync.RWMutex
+	config       *Config
+	httpClient   *resty.Client
+	streamClient *http.Client
+	cache        *lruCache
+	cacheMutex   sync.RWMutex
+	stats        clientStats
+	tracer       trace.Tracer
+	health       healthGate
+	logger       Logger
+	limiter      *rate.Limiter   // nil when rate limiting is disabled
+	breaker      *circuitBreaker // nil when the circuit breaker is disabled
+	tokens       *tokenSource    // nil unless OAuth2 is configured
+	metrics      *clientMetrics  // nil unless Prometheus metrics are enabled
+	budget       *retryBudget    // nil unless RetryBudget is set
+	etags        *etagStore      // nil unless ConditionalRequests is set
+
+	// Per-agent cache partitions, used when CachePartitionByAgent is set
+	partitions  map[string]*lruCache
+	cacheAgents map[string]string // cache key -> agent ID
+
+	// Cache effectiveness counters, updated atomically
+	cacheCounters cacheCounters
+
+	// Clients for per-request timeout and retry overrides, keyed by
+	// "timeout/retries"
+	derivedClients sync.Map
+
+	// Serializes offline queue flushes so memories are replayed once, in order
+	offlineMu sync.Mutex
+
+	// closed is closed by Close to stop background work, which background
+	// tracks so that Close can wait for it. closeMu orders starting
+	// background work against Close.
+	closeMu    sync.Mutex
+	closed     chan struct{}
+	background sync.WaitGroup
 }
 
-// NewClient creates a new AgentMem client with the provided configuration
+// NewClient creates a new AgentMem client with the provided configuration.
+// The client keeps its own copy of config, so the caller may keep reusing or
+// mutating config afterwards without affecting the client.
 func NewClient(config *Config) (*Client, error) {
+	config = config.Clone()
 	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
+	metrics, err := newClientMetrics(config.PrometheusRegisterer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register metrics: %w", err)
+	}
 
 	client := &Client{
-		config: config,
-		cache:  make(map[string]*cacheEntry),
+		config:      config,
+		cache:       newLRUCache(config.MaxCacheEntries),
+		partitions:  make(map[string]*lruCache),
+		cacheAgents: make(map[string]string),
+		tracer:      newTracer(config.TracerProvider),
+		logger:      newLogger(config),
+		limiter:     newRateLimiter(config),
+		breaker:     newCircuitBreaker(config.CircuitBreakerThreshold, config.CircuitBreakerCooldown),
+		metrics:     metrics,
+		budget:      newRetryBudget(config.RetryBudget),
+		etags:       newETagStore(config.ConditionalRequests, config.MaxCacheEntries),
+		closed:      make(chan struct{}),
 	}
 
 	client.setupHTTPClient()
+	if config.OAuth2 != nil {
+		tokenClient := &http.Client{Transport: client.httpClient.GetClient().Transport, Timeout: config.Timeout}
+		client.tokens = newTokenSource(*config.OAuth2, tokenClient)
+	}
 	return client, nil
 }
 
@@ -51,51 +121,174 @@ func NewClientFromEnv() (*Client, error) {
 
 // setupHTTPClient configures the HTTP client
 func (c *Client) setupHTTPClient() {
-	c.httpClient = resty.New()
-	c.httpClient.SetBaseURL(c.config.GetAPIBaseURL())
-	c.httpClient.SetTimeout(c.config.Timeout)
-	c.httpClient.SetHeaders(c.config.GetDefaultHeaders())
+	var httpClient *http.Client
+	if c.config.HTTPClient != nil {
+		// resty sets the timeout on the client it is given, so work on a copy
+		clientCopy := *c.config.HTTPClient
+		httpClient = &clientCopy
+	} else {
+		httpClient = &http.Client{Transport: c.newTransport()}
+	}
+	c.httpClient = c.newRestyClient(httpClient, c.config.Timeout, c.config.MaxRetries)
+
+	// Streaming requests share the transport but must not be bound by the
+	// unary request Timeout
+	c.streamClient = &http.Client{Transport: c.httpClient.GetClient().Transport}
+}
+
+// newTransport creates the transport used when no HTTPClient is configured,
+// with the configured connection pool, phase timeout, and proxy settings.
+// Without a ProxyURL, the proxy is taken from the environment.
+func (c *Client) newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = c.proxy()
+	transport.DialContext = c.netDialer().DialContext
+	transport.TLSHandshakeTimeout = c.config.TLSHandshakeTimeout
+	transport.ResponseHeaderTimeout = c.config.ResponseHeaderTimeout
+	transport.MaxIdleConns = c.config.MaxIdleConns
+	transport.MaxIdleConnsPerHost = c.config.MaxIdleConnsPerHost
+	transport.IdleConnTimeout = c.config.IdleConnTimeout
+	return transport
+}
+
+// netDialer returns the dialer for connections the client opens itself,
+// bounded by DialTimeout
+func (c *Client) netDialer() *net.Dialer {
+	return &net.Dialer{Timeout: c.config.DialTimeout, KeepAlive: 30 * time.Second}
+}
+
+// proxy returns the proxy selection for connections the client opens
+// itself: Config.ProxyURL when set, the environment otherwise
+func (c *Client) proxy() func(*http.Request) (*url.URL, error) {
+	if c.config.ProxyURL != "" {
+		if proxyURL, err := url.Parse(c.config.ProxyURL); err == nil {
+			return http.ProxyURL(proxyURL)
+		}
+	}
+	return http.ProxyFromEnvironment
+}
+
+// newRestyClient creates a resty client with the given per-attempt timeout
+// and retry count. A nil httpClient creates a new transport.
+func (c *Client) newRestyClient(httpClient *http.Client, timeout time.Duration, retries int) *resty.Client {
+	var client *resty.Client
+	if httpClient != nil {
+		client = resty.NewWithClient(httpClient)
+	} else {
+		client = resty.New()
+	}
+	client.SetBaseURL(c.config.GetAPIBaseURL())
+	client.SetTimeout(timeout)
+	client.SetHeaders(c.config.GetDefaultHeaders())
+	client.SetJSONMarshaler(c.marshalJSON)
+	client.SetJSONUnmarshaler(c.unmarshalJSON)
 
-	// Enable compression if configured
+	// Enable compression if configured. Only gzip is offered, as it is the
+	// only encoding resty decodes.
 	if c.config.EnableCompression {
-		c.httpClient.SetHeader("Accept-Encoding", "gzip, deflate")
+		client.SetHeader("Accept-Encoding", "gzip")
 	}
 
+	// The limit applies to the decompressed body, guarding against
+	// decompression bombs as well as oversized responses
+	client.SetResponseBodyLimit(c.config.MaxResponseBytes)
+
 	// Setup retry logic
-	c.httpClient.SetRetryCount(c.config.MaxRetries)
-	c.httpClient.SetRetryWaitTime(c.config.RetryDelay)
-	c.httpClient.SetRetryMaxWaitTime(c.config.RetryDelay * 10)
+	client.SetRetryCount(retries)
+	client.SetRetryWaitTime(c.config.RetryDelay)
+	client.SetRetryMaxWaitTime(maxRetryAfterWait)
+	client.SetRetryAfter(c.retryAfter)
+	if c.config.GetBackoff() == BackoffExponentialJitter {
+		// resty raises computed waits to at least RetryWaitTime, which
+		// would defeat full jitter
+		client.SetRetryWaitTime(0)
+	}
 
-	// Retry on server errors and network errors
-	c.httpClient.AddRetryCondition(func(r *resty.Response, err error) bool {
-		return err != nil || r.StatusCode() >= 500
+	// Retry on rate limiting, server errors, and network errors, within
+	// the retry budget
+	client.AddRetryCondition(c.shouldRetryWithinBudget)
+	client.AddRetryHook(func(resp *resty.Response, err error) {
+		c.onRetry(retries, resp, err)
 	})
+	client.SetPreRequestHook(c.preRequestHook)
 
 	// Setup logging if enabled
 	if c.config.EnableLogging {
-		c.httpClient.SetLogger(log.Default())
-		c.httpClient.EnableTrace()
+		client.SetLogger(c.logger)
+		client.EnableTrace()
+		client.SetDebug(true)
+		// Debug logs include headers and bodies, so mask credentials
+		client.OnRequestLog(c.redactRequestLog)
+		client.OnResponseLog(c.redactResponseLog)
 	}
 
 	// Response middleware for error handling
-	c.httpClient.OnAfterResponse(func(client *resty.Client, resp *resty.Response) error {
+	client.OnAfterResponse(func(client *resty.Client, resp *resty.Response) error {
+		c.stats.recordResponse(resp)
+		if c.config.EnableLogging {
+			c.logResponse(resp)
+		}
+		if resp.StatusCode() == http.StatusUnauthorized && c.tokens != nil {
+			c.tokens.invalidate()
+		}
 		if resp.IsError() {
-			var errorMsg string
-			if resp.Body() != nil {
-				var apiResp APIResponse
-				if err := json.Unmarshal(resp.Body(), &apiResp); err == nil && apiResp.Error != nil {
-					errorMsg = *apiResp.Error
-				} else if apiResp.Message != nil {
-					errorMsg = *apiResp.Message
-				}
-			}
-			if errorMsg == "" {
-				errorMsg = fmt.Sprintf("HTTP %d: %s", resp.StatusCode(), resp.Status())
+			err := handleHTTPError(resp.StatusCode(), errorMessage(resp.StatusCode(), resp.Status(), resp.Body()))
+			withRequestID(err, c.responseRequestID(resp.Header()))
+			if rateLimitErr, ok := err.(*RateLimitError); ok {
+				rateLimitErr.RetryAfter, _ = responseRetryAfter(resp)
 			}
-			return handleHTTPError(resp.StatusCode(), errorMsg)
+			return err
 		}
 		return nil
 	})
+
+	return client
+}
+
+// clientFor returns the resty client honoring the timeout and retry
+// overrides of opts. Clients for overrides share the transport of the
+// default client and are created once per distinct override.
+func (c *Client) clientFor(opts RequestOptions) *resty.Client {
+	if opts.Timeout == nil && opts.Retries == nil {
+		return c.httpClient
+	}
+
+	timeout := c.config.Timeout
+	if opts.Timeout != nil {
+		timeout = *opts.Timeout
+	}
+	retries := c.config.MaxRetries
+	if opts.Retries != nil {
+		retries = *opts.Retries
+	}
+	if timeout == c.config.Timeout && retries == c.config.MaxRetries {
+		return c.httpClient
+	}
+
+	key := fmt.Sprintf("%s/%d", timeout, retries)
+	if client, ok := c.derivedClients.Load(key); ok {
+		return client.(*resty.Client)
+	}
+	httpClient := *c.httpClient.GetClient()
+	client, _ := c.derivedClients.LoadOrStore(key, c.newRestyClient(&httpClient, timeout, retries))
+	return client.(*resty.Client)
+}
+
+// errorMessage extracts the error message from an API error response body
+func errorMessage(statusCode int, status string, body []byte) string {
+	var errorMsg string
+	if body != nil {
+		var apiResp APIResponse
+		if err := json.Unmarshal(body, &apiResp); err == nil && apiResp.Error != nil {
+			errorMsg = *apiResp.Error
+		} else if apiResp.Message != nil {
+			errorMsg = *apiResp.Message
+		}
+	}
+	if errorMsg == "" {
+		errorMsg = fmt.Sprintf("HTTP %d: %s", statusCode, status)
+	}
+	return errorMsg
 }
 
 // getCacheKey generates a cache key for the request
@@ -109,63 +302,259 @@ func (c *Client) getCacheKey(method, endpoint string, params interface{}) string
 	return key
 }
 
-// getFromCache retrieves data from cache if valid
+// getFromCache retrieves data from cache if valid, marking it as recently used
 func (c *Client) getFromCache(key string) (interface{}, bool) {
 	if !c.config.EnableCaching {
 		return nil, false
 	}
 
-	c.cacheMutex.RLock()
-	defer c.cacheMutex.RUnlock()
+	var data interface{}
+	var found bool
+	if c.config.Cache != nil {
+		data, found = c.config.Cache.Get(key)
+	} else {
+		c.cacheMutex.Lock()
+		data, found = c.getCacheEntry(key)
+		c.cacheMutex.Unlock()
+	}
 
-	entry, exists := c.cache[key]
+	if found {
+		atomic.AddInt64(&c.cacheCounters.hits, 1)
+	} else {
+		atomic.AddInt64(&c.cacheCounters.misses, 1)
+	}
+	c.metrics.recordCacheLookup(found)
+	return data, found
+}
+
+// getCacheEntry looks up a valid entry, dropping it if it has expired.
+// The caller must hold cacheMutex.
+func (c *Client) getCacheEntry(key string) (interface{}, bool) {
+	if agentID, partitioned := c.cacheAgents[key]; partitioned {
+		return c.getFromPartition(agentID, key)
+	}
+
+	entry, exists := c.cache.get(key)
 	if !exists {
 		return nil, false
 	}
 
 	// Check if cache entry is still valid
-	if time.Since(entry.timestamp) > c.config.CacheTTL {
+	if entry.expired(c.config.CacheTTL) {
 		// Cache expired, remove it
-		delete(c.cache, key)
+		c.cache.remove(key)
 		return nil, false
 	}
 
 	return entry.data, true
 }
 
-// setCache stores data in cache
-func (c *Client) setCache(key string, data interface{}) {
+// setCache stores data in cache. A zero ttl uses the configured CacheTTL.
+// When partitioning by agent is enabled, entries belonging to an agent are
+// stored in that agent's partition of the built-in cache.
+func (c *Client) setCache(key string, data interface{}, agentID string, ttl time.Duration) {
 	if !c.config.EnableCaching {
 		return
 	}
+	// The caller keeps data, so cache a copy it cannot modify
+	data = c.cacheCopy(data)
+
+	if c.config.Cache != nil {
+		if ttl <= 0 {
+			ttl = c.config.CacheTTL
+		}
+		c.config.Cache.Set(key, data, ttl)
+		return
+	}
 
 	c.cacheMutex.Lock()
 	defer c.cacheMutex.Unlock()
 
-	c.cache[key] = &cacheEntry{
+	entry := &cacheEntry{
 		data:      data,
 		timestamp: time.Now(),
+		ttl:       ttl,
+	}
+
+	if c.config.CachePartitionByAgent && agentID != "" {
+		c.setInPartition(agentID, key, entry)
+		return
+	}
+
+	atomic.AddInt64(&c.cacheCounters.evictions, int64(len(c.cache.set(key, entry))))
+}
+
+// cacheAgentID determines which agent a cached response belongs to
+func cacheAgentID(params interface{}, result interface{}) string {
+	if memory, ok := result.(*Memory); ok {
+		return memory.AgentID
+	}
+	if queryParams, ok := params.(map[string]interface{}); ok {
+		if agentID, ok := queryParams["agent_id"].(string); ok {
+			return agentID
+		}
+	}
+	return ""
+}
+
+// memoryCacheKey returns the per-ID cache key for a memory. The projection
+// is part of the key so that a projected entry is never served to a caller
+// expecting the full record.
+func (c *Client) memoryCacheKey(memoryID string, projection Projection) string {
+	endpoint := fmt.Sprintf("/memories/%s", memoryID)
+	if projection.ExcludeEmbedding {
+		return c.getCacheKey("GET", endpoint, map[string]interface{}{"exclude_embedding": true})
+	}
+	return c.getCacheKey("GET", endpoint, nil)
+}
+
+// getCachedMemory looks up a memory in the per-ID cache. A cached full
+// record also satisfies a projected lookup.
+func (c *Client) getCachedMemory(memoryID string, projection Projection) (*Memory, bool) {
+	cachedData, found := c.getFromCache(c.memoryCacheKey(memoryID, Projection{}))
+	if !found && projection.ExcludeEmbedding {
+		cachedData, found = c.getFromCache(c.memoryCacheKey(memoryID, projection))
+	}
+	if !found {
+		return nil, false
+	}
+
+	var memory Memory
+	if err := c.decodeCached(cachedData, &memory); err != nil {
+		return nil, false
+	}
+	if projection.ExcludeEmbedding {
+		memory.Embedding = nil
 	}
+	return &memory, true
+}
+
+// cacheCopy returns a deep copy of a response about to be cached. Memories
+// are cloned; other responses, which are pointers to decoded JSON, are
+// copied through a JSON round trip.
+func (c *Client) cacheCopy(data interface{}) interface{} {
+	if memory, ok := data.(*Memory); ok {
+		return memory.Clone()
+	}
+	value := reflect.ValueOf(data)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return data
+	}
+	copied := reflect.New(value.Type().Elem())
+	if err := c.decodeCached(data, copied.Interface()); err != nil {
+		return data
+	}
+	return copied.Interface()
+}
+
+// decodeCached copies cached data into result, so the caller may modify
+// result without affecting the cache
+func (c *Client) decodeCached(cachedData interface{}, result interface{}) error {
+	if memory, ok := cachedData.(*Memory); ok {
+		if target, ok := result.(*Memory); ok {
+			*target = *memory.Clone()
+			return nil
+		}
+	}
+	resultBytes, err := c.marshalJSON(cachedData)
+	if err != nil {
+		return err
+	}
+	return c.unmarshalJSON(resultBytes, result)
 }
 
 // makeRequest performs an HTTP request with caching support
 func (c *Client) makeRequest(ctx context.Context, method, endpoint string, body interface{}, result interface{}, useCache bool) error {
-	// Check cache for GET requests
-	if method == "GET" && useCache {
+	return c.makeRequestWithOptions(ctx, method, endpoint, body, result, RequestOptions{UseCache: &useCache})
+}
+
+// makeRequestWithOptions performs an HTTP request honoring per-request
+// options. When ctx is cancelled or its deadline passes, the request and
+// any pending retry stop at once and a *CancelledError wrapping ctx's error
+// is returned, so errors.Is(err, context.Canceled) and
+// errors.Is(err, context.DeadlineExceeded) work.
+func (c *Client) makeRequestWithOptions(ctx context.Context, method, endpoint string, body interface{}, result interface{}, opts RequestOptions) (err error) {
+	ctx, span := c.startSpan(ctx, method, endpoint, body)
+	defer func() { endSpan(span, err) }()
+
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return NewCancelledError(ctxErr)
+	}
+
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	if err := c.checkHealthGate(endpoint, opts); err != nil {
+		return err
+	}
+
+	useCache := opts.UseCache != nil && *opts.UseCache
+
+	// Check cache for GET requests, unless the server must see the read
+	if method == "GET" && useCache && !opts.RecordAccess {
 		cacheKey := c.getCacheKey(method, endpoint, body)
 		if cachedData, found := c.getFromCache(cacheKey); found {
-			if c.config.EnableLogging {
-				log.Printf("[AgentMem] Cache hit for %s %s", method, endpoint)
-			}
+			c.logger.Debugf("Cache hit for %s %s", method, endpoint)
 			// Copy cached data to result
-			if resultBytes, err := json.Marshal(cachedData); err == nil {
-				return json.Unmarshal(resultBytes, result)
+			if err := c.decodeCached(cachedData, result); err == nil {
+				return nil
 			}
 		}
 	}
 
+	resp, requestID, err := c.send(ctx, span, method, endpoint, body, opts)
+	if err != nil {
+		return err
+	}
+
+	// Decode the body regardless of its Content-Type so that an empty or
+	// malformed success response is reported rather than silently ignored
+	if result != nil && resp.StatusCode() != http.StatusNoContent {
+		if err := c.unmarshalJSON(resp.Body(), result); err != nil {
+			return withRequestID(NewDecodeError(resp.StatusCode(), err), requestID)
+		}
+	}
+
+	// Cache successful GET responses
+	if method == "GET" && useCache && resp.IsSuccess() && result != nil {
+		cacheKey := c.getCacheKey(method, endpoint, body)
+		var ttl time.Duration
+		if opts.CacheTTL != nil {
+			ttl = *opts.CacheTTL
+		}
+		c.setCache(cacheKey, result, cacheAgentID(body, result), ttl)
+	}
+
+	return nil
+}
+
+// send sends a request through the rate limiter and circuit breaker, with
+// retries, and returns the final response along with the request ID it was
+// tagged with. An API error is returned together with its response.
+func (c *Client) send(ctx context.Context, span trace.Span, method, endpoint string, body interface{}, opts RequestOptions) (*resty.Response, string, error) {
+	// Reject a programming error before it reaches the rate limiter or the
+	// circuit breaker, neither of which should see a request never sent
+	switch method {
+	case "GET", "POST", "PUT", "DELETE":
+	default:
+		return nil, "", fmt.Errorf("unsupported HTTP method: %s", method)
+	}
+
+	if err := c.waitForRateLimit(ctx); err != nil {
+		return nil, "", err
+	}
+
 	// Prepare request
-	req := c.httpClient.R().SetContext(ctx)
+	req := c.clientFor(opts).R().SetContext(ctx)
+	req.SetHeaders(opts.Headers)
+	injectTraceContext(ctx, req.Header)
+
+	// Tag the request with a correlation ID, reused across retries
+	var requestID string
+	if c.config.RequestIDHeader != "" {
+		requestID = c.requestID(ctx)
+		req.SetHeader(c.config.RequestIDHeader, requestID)
+	}
 
 	if body != nil {
 		if method == "GET" {
@@ -176,17 +565,26 @@ func (c *Client) makeRequest(ctx context.Context, method, endpoint string, body
 				}
 			}
 		} else {
-			req.SetBody(body)
+			payload, compressed, encodeErr := c.compressBody(body)
+			if encodeErr != nil {
+				return nil, requestID, encodeErr
+			}
+			if compressed {
+				req.SetHeader("Content-Encoding", "gzip")
+			}
+			req.SetBody(payload)
 		}
 	}
 
-	if result != nil {
-		req.SetResult(result)
+	if err := c.breaker.allow(); err != nil {
+		return nil, requestID, err
 	}
+	c.budget.deposit()
 
 	// Make request
 	var resp *resty.Response
 	var err error
+	start := time.Now()
 
 	switch method {
 	case "GET":
@@ -197,35 +595,94 @@ func (c *Client) makeRequest(ctx context.Context, method, endpoint string, body
 		resp, err = req.Put(endpoint)
 	case "DELETE":
 		resp, err = req.Delete(endpoint)
-	default:
-		return fmt.Errorf("unsupported HTTP method: %s", method)
 	}
+	recordResponse(span, resp)
+	c.metrics.observeRequest(ctx, method, endpoint, resp, time.Since(start))
+	c.runResponseHooks(resp, err)
 
 	if err != nil {
-		return NewNetworkError(fmt.Sprintf("Request failed: %v", err))
+		err = withRequestID(requestError(ctx, err), requestID)
+		c.breaker.record(err)
+		return resp, requestID, err
 	}
+	c.breaker.record(nil)
+	return resp, requestID, nil
+}
 
-	// Cache successful GET responses
-	if method == "GET" && useCache && resp.IsSuccess() && result != nil {
-		cacheKey := c.getCacheKey(method, endpoint, body)
-		c.setCache(cacheKey, result)
+// requestError classifies an error returned by the HTTP client. Context
+// cancellation, API errors, and oversized responses are returned as such;
+// anything else is treated as a network failure.
+func requestError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return NewCancelledError(ctxErr)
 	}
+	if _, ok := asAgentMemError(err); ok {
+		return err
+	}
+	if errors.Is(err, resty.ErrResponseBodyTooLarge) {
+		return NewResponseTooLargeError()
+	}
+	return NewNetworkError(fmt.Sprintf("Request failed: %v", err))
+}
 
-	return nil
+// prepareCreateParams applies client-side policies to memory creation
+// params and validates the result before it is sent. The caller's params
+// are not modified.
+func (c *Client) prepareCreateParams(params CreateMemoryParams) (CreateMemoryParams, error) {
+	if params.Importance != nil && c.config.ImportanceScaler != nil {
+		importance := c.config.ImportanceScaler(*params.Importance)
+		params.Importance = &importance
+	}
+	if err := params.Validate(); err != nil {
+		return params, err
+	}
+	if params.ImportanceLevel != 0 {
+		importance := params.ImportanceLevel.ToFloat()
+		params.Importance = &importance
+		params.ImportanceLevel = 0
+	}
+	return params, nil
 }
 
-// AddMemory adds a new memory
+// AddMemory adds a new memory. With an ImportanceScorer configured, a
+// memory without an importance is scored before it is sent; otherwise the
+// DefaultImportanceByType for its memory type applies. With an
+// OfflineStore configured, a memory
+// that cannot be sent because of a network failure is queued instead and a
+// provisional ID is returned (see IsLocalID); FlushOfflineQueue sends it
+// later and reports its server ID to OnOfflineFlush.
 func (c *Client) AddMemory(ctx context.Context, params CreateMemoryParams) (string, error) {
+	params, err := c.prepareCreateParams(params)
+	if err != nil {
+		return "", err
+	}
+	params, err = c.fillImportance(ctx, params)
+	if err != nil {
+		return "", err
+	}
+	params.IdempotencyKey = c.idempotencyKey(params.IdempotencyKey)
+
 	var response CreateMemoryResponse
-	err := c.makeRequest(ctx, "POST", "/memories", params, &response, false)
+	err = c.makeRequestWithOptions(ctx, "POST", "/memories", params, &response, idempotencyOptions(params.IdempotencyKey))
 	if err != nil {
+		var networkErr *NetworkError
+		if c.config.OfflineStore != nil && errors.As(err, &networkErr) {
+			return c.enqueueOffline(params)
+		}
 		return "", err
 	}
+	c.invalidateAgentAggregates(params.AgentID)
 	return response.ID, nil
 }
 
-// GetMemory retrieves a memory by ID
+// GetMemory retrieves a memory by ID. Reads served from the cache are not
+// seen by the server, so they do not update AccessCount and LastAccessed;
+// use GetMemoryWithOptions with RecordAccess for a tracked read.
 func (c *Client) GetMemory(ctx context.Context, memoryID string) (*Memory, error) {
+	if c.config.ConditionalRequests {
+		return c.getMemoryConditional(ctx, memoryID)
+	}
+
 	var memory Memory
 	err := c.makeRequest(ctx, "GET", fmt.Sprintf("/memories/%s", memoryID), nil, &memory, true)
 	if err != nil {
@@ -234,41 +691,522 @@ func (c *Client) GetMemory(ctx context.Context, memoryID string) (*Memory, error
 	return &memory, nil
 }
 
-// UpdateMemory updates an existing memory
+// GetMemoryWithOptions retrieves a memory by ID with per-request options.
+// The embedding vector is omitted unless opts.IncludeEmbedding is set,
+// which keeps responses and cache entries small. With opts.RecordAccess the
+// memory is always fetched from the server, which records the access, and
+// the fresh copy replaces any cached one.
+func (c *Client) GetMemoryWithOptions(ctx context.Context, memoryID string, opts RequestOptions) (*Memory, error) {
+	if opts.UseCache == nil {
+		useCache := true
+		opts.UseCache = &useCache
+	}
+
+	queryParams := map[string]interface{}{"exclude_embedding": true}
+	if opts.IncludeEmbedding {
+		queryParams = map[string]interface{}{"include_embedding": true}
+	} else if *opts.UseCache && !opts.RecordAccess {
+		// A cached full record also satisfies the projected read
+		if memory, found := c.getCachedMemory(memoryID, Projection{ExcludeEmbedding: true}); found {
+			return memory, nil
+		}
+	}
+
+	var memory Memory
+	err := c.makeRequestWithOptions(ctx, "GET", fmt.Sprintf("/memories/%s", memoryID), queryParams, &memory, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &memory, nil
+}
+
+// GetMemoryWithEmbedding retrieves a memory by ID, asking the server to
+// include the embedding vector even when its default projection omits it
+func (c *Client) GetMemoryWithEmbedding(ctx context.Context, memoryID string) (*Memory, error) {
+	var memory Memory
+	queryParams := map[string]interface{}{
+		"include_embedding": true,
+	}
+	err := c.makeRequest(ctx, "GET", fmt.Sprintf("/memories/%s", memoryID), queryParams, &memory, true)
+	if err != nil {
+		return nil, err
+	}
+	return &memory, nil
+}
+
+// GetMemories retrieves multiple memories by ID in one request. The result
+// is aligned with memoryIDs by position; entries for IDs that were not found
+// are nil. Duplicate IDs are fetched once but yield one independent copy per
+// position, so the result always has len(memoryIDs) entries. Memories
+// already in the per-ID cache are served from it and only the remaining IDs
+// are fetched.
+func (c *Client) GetMemories(ctx context.Context, memoryIDs []string, projection Projection) ([]*Memory, error) {
+	memories := make([]*Memory, len(memoryIDs))
+	seen := make(map[string]bool, len(memoryIDs))
+	var missing []string
+	var duplicates int
+	for i, memoryID := range memoryIDs {
+		if memoryID == "" {
+			return nil, NewValidationError(fmt.Sprintf("memory ID at index %d is empty", i))
+		}
+		if seen[memoryID] {
+			duplicates++
+			continue
+		}
+		seen[memoryID] = true
+		if memory, found := c.getCachedMemory(memoryID, projection); found {
+			memories[i] = memory
+			continue
+		}
+		missing = append(missing, memoryID)
+	}
+	if duplicates > 0 {
+		c.logger.Warnf("GetMemories called with %d duplicate memory IDs", duplicates)
+	}
+
+	found := make(map[string]*Memory, len(seen))
+	for i, memoryID := range memoryIDs {
+		if memories[i] != nil {
+			found[memoryID] = memories[i]
+		}
+	}
+
+	if len(missing) > 0 {
+		params := BatchGetMemoryParams{
+			IDs:              missing,
+			ExcludeEmbedding: projection.ExcludeEmbedding,
+		}
+		var response BatchGetResponse
+		err := c.makeRequest(ctx, "POST", "/memories/batch/get", params, &response, false)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range response.Memories {
+			memory := &response.Memories[i]
+			found[memory.ID] = memory
+			c.setCache(c.memoryCacheKey(memory.ID, projection), memory, memory.AgentID, 0)
+		}
+	}
+
+	assigned := make(map[string]bool, len(found))
+	for i, memoryID := range memoryIDs {
+		memory, ok := found[memoryID]
+		if !ok {
+			continue
+		}
+		if assigned[memoryID] {
+			// Give each duplicate position its own copy
+			var copied Memory
+			if err := c.decodeCached(memory, &copied); err != nil {
+				return nil, err
+			}
+			memory = &copied
+		}
+		assigned[memoryID] = true
+		memories[i] = memory
+	}
+	return memories, nil
+}
+
+// BatchGetMemories retrieves full memory records by ID in one request,
+// returned in the order requested with nil entries for IDs that were not
+// found. Fetched memories populate the per-ID cache, so later GetMemory
+// calls for them are cache hits. See GetMemories for projected fetches.
+func (c *Client) BatchGetMemories(ctx context.Context, memoryIDs []string) ([]*Memory, error) {
+	return c.GetMemories(ctx, memoryIDs, Projection{})
+}
+
+// UpdateMemory updates an existing memory. By default Metadata replaces the
+// memory's metadata; set MetadataMergeMode to MetadataMerge to change only
+// the given keys without a read-modify-write cycle.
 func (c *Client) UpdateMemory(ctx context.Context, memoryID string, params UpdateMemoryParams) (*Memory, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
 	var memory Memory
 	err := c.makeRequest(ctx, "PUT", fmt.Sprintf("/memories/%s", memoryID), params, &memory, false)
 	if err != nil {
 		return nil, err
 	}
+	c.invalidateMemory(memoryID, memory.AgentID)
 	return &memory, nil
 }
 
 // DeleteMemory deletes a memory
 func (c *Client) DeleteMemory(ctx context.Context, memoryID string) error {
-	return c.makeRequest(ctx, "DELETE", fmt.Sprintf("/memories/%s", memoryID), nil, nil, false)
+	err := c.makeRequest(ctx, "DELETE", fmt.Sprintf("/memories/%s", memoryID), nil, nil, false)
+	if err != nil {
+		return err
+	}
+	c.invalidateMemory(memoryID, "")
+	return nil
 }
 
-// SearchMemories searches for memories
+// SearchMemories searches for memories. An empty slice with a nil error
+// means the search succeeded with no matches; an empty or malformed response
+// body is reported as a *DecodeError.
+//
+// When query.Limit is 0, all matches are fetched page by page and returned
+// together, up to Config.MaxSearchResults. If more matches remain, the
+// results fetched so far are returned with ErrSearchTruncated.
 func (c *Client) SearchMemories(ctx context.Context, query SearchQuery) ([]SearchResult, error) {
-	var response SearchResponse
-	err := c.makeRequest(ctx, "POST", "/memories/search", query, &response, false)
+	return c.SearchMemoriesWithOptions(ctx, query, RequestOptions{})
+}
+
+// SearchMemoriesWithOptions searches for memories with per-request options,
+// e.g. a longer Timeout for an expensive search. A query.Limit of 0 fetches
+// all matches, as with SearchMemories.
+func (c *Client) SearchMemoriesWithOptions(ctx context.Context, query SearchQuery, opts RequestOptions) ([]SearchResult, error) {
+	if query.Limit == 0 {
+		return c.searchAll(ctx, query, opts)
+	}
+	response, err := c.searchPage(ctx, query, opts)
 	if err != nil {
 		return nil, err
 	}
 	return response.Results, nil
 }
 
-// BatchAddMemories adds multiple memories in batch
+// searchAll pages through all matches of a query, stopping at
+// MaxSearchResults
+func (c *Client) searchAll(ctx context.Context, query SearchQuery, opts RequestOptions) ([]SearchResult, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	// Embed once rather than for every page
+	query, err := c.embedQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []SearchResult{}
+	for {
+		remaining := c.config.GetMaxSearchResults() - len(results)
+		if remaining <= 0 {
+			return results, ErrSearchTruncated
+		}
+		query.Limit = min(searchPageSize, remaining)
+
+		response, err := c.searchPage(ctx, query, opts)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, response.Results...)
+		if response.NextCursor == "" || len(response.Results) == 0 {
+			return results, nil
+		}
+		query.Cursor = response.NextCursor
+		if query.SnapshotID == nil && response.SnapshotID != "" {
+			query.SnapshotID = &response.SnapshotID
+		}
+	}
+}
+
+// SearchMemoriesPage searches for memories and returns the full search
+// response, including the total match count when query.IncludeTotal is set
+func (c *Client) SearchMemoriesPage(ctx context.Context, query SearchQuery) (*SearchResponse, error) {
+	return c.searchPage(ctx, query, RequestOptions{})
+}
+
+// searchPage performs a search request
+func (c *Client) searchPage(ctx context.Context, query SearchQuery, opts RequestOptions) (*SearchResponse, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	query, err := c.embedQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var response SearchResponse
+	err = c.makeRequestWithOptions(ctx, "POST", "/memories/search", query.wireQuery(), &response, opts)
+	if err != nil {
+		return nil, err
+	}
+	if response.Results == nil {
+		response.Results = []SearchResult{}
+	}
+	highlightResults(query, response.Results)
+	return &response, nil
+}
+
+// ListMemories retrieves one page of an agent's memories. Pass the
+// returned NextCursor as params.Cursor to fetch the following page; an empty
+// NextCursor means all memories have been listed.
+func (c *Client) ListMemories(ctx context.Context, params ListMemoriesParams) (*ListMemoriesResult, error) {
+	if params.AgentID == "" {
+		return nil, NewValidationError("agent ID is required")
+	}
+
+	queryParams := map[string]interface{}{
+		"agent_id": params.AgentID,
+	}
+	if params.PageSize > 0 {
+		queryParams["page_size"] = params.PageSize
+	}
+	if params.Cursor != "" {
+		queryParams["cursor"] = params.Cursor
+	}
+	if params.MemoryType != nil {
+		queryParams["memory_type"] = string(*params.MemoryType)
+	}
+	if params.MinImportance != nil {
+		if err := validateImportance("min importance", *params.MinImportance); err != nil {
+			return nil, err
+		}
+		queryParams["min_importance"] = *params.MinImportance
+	}
+	if params.SortBy != "" {
+		if err := validateSortField(params.SortBy); err != nil {
+			return nil, err
+		}
+		queryParams["sort_by"] = string(params.SortBy)
+	}
+	if params.SnapshotID != "" {
+		queryParams["snapshot_id"] = params.SnapshotID
+	}
+
+	var result ListMemoriesResult
+	err := c.makeRequest(ctx, "GET", "/memories", queryParams, &result, false)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SearchByVector finds the memories nearest to vector. All filters set on
+// query (agent, memory type, importance, age, metadata) are applied
+// alongside the vector similarity; any text query on it is ignored.
+func (c *Client) SearchByVector(ctx context.Context, vector []float64, query SearchQuery) ([]SearchResult, error) {
+	if len(vector) == 0 {
+		return nil, NewValidationError("vector query must not be empty")
+	}
+	query.TextQuery = nil
+	query.VectorQuery = vector
+	query.HybridWeight = nil
+	return c.SearchMemories(ctx, query)
+}
+
+// SearchSimilar finds the memories of the same agent most similar to an
+// existing memory, by vector search on its embedding, excluding the memory
+// itself. A limit of 0 returns all matches, as with SearchMemories.
+func (c *Client) SearchSimilar(ctx context.Context, memoryID string, limit int) ([]SearchResult, error) {
+	if limit < 0 {
+		return nil, NewValidationError("limit must not be negative")
+	}
+	// Ask for the embedding explicitly: a plain read may be served a cached
+	// or server-side projection without it
+	source, err := c.GetMemoryWithEmbedding(ctx, memoryID)
+	if err != nil {
+		return nil, err
+	}
+	if len(source.Embedding) == 0 {
+		return nil, NewValidationError(fmt.Sprintf("memory %s has no embedding, so it cannot be used for vector search", memoryID))
+	}
+
+	query := SearchQuery{AgentID: source.AgentID, VectorQuery: source.Embedding}
+	if limit > 0 {
+		// One extra result makes up for the source memory matching itself
+		query.Limit = limit + 1
+	}
+	results, err := c.SearchMemories(ctx, query)
+	if err != nil && !errors.Is(err, ErrSearchTruncated) {
+		return nil, err
+	}
+
+	similar := make([]SearchResult, 0, len(results))
+	for _, result := range results {
+		if result.Memory.ID != source.ID {
+			similar = append(similar, result)
+		}
+	}
+	if limit > 0 && len(similar) > limit {
+		similar = similar[:limit]
+	}
+	return similar, err
+}
+
+// HybridSearch searches by both text and vector similarity, applying all
+// filters set on query alongside them. Set query.HybridWeight to control how
+// the server fuses the two scores.
+func (c *Client) HybridSearch(ctx context.Context, text string, vector []float64, query SearchQuery) ([]SearchResult, error) {
+	if text == "" {
+		return nil, NewValidationError("text query must not be empty")
+	}
+	if len(vector) == 0 {
+		return nil, NewValidationError("vector query must not be empty")
+	}
+	query.TextQuery = &text
+	query.VectorQuery = vector
+	return c.SearchMemories(ctx, query)
+}
+
+// BatchAddMemories adds multiple memories in batch. Memories without an
+// importance are scored or given a default importance as with AddMemory,
+// once all of them are valid. With DryRun set, the memories are only
+// validated: nothing is sent, and if any memory is invalid a
+// *BatchValidationError lists every failure.
 func (c *Client) BatchAddMemories(ctx context.Context, params BatchCreateMemoryParams) ([]string, error) {
+	if params.DryRun {
+		if result := c.ValidateBatch(params); !result.Valid() {
+			return nil, NewBatchValidationError(result, len(params.Memories))
+		}
+		return nil, nil
+	}
+
+	memories := make([]CreateMemoryParams, len(params.Memories))
+	for i, memory := range params.Memories {
+		prepared, err := c.prepareCreateParams(memory)
+		if err != nil {
+			return nil, NewValidationError(fmt.Sprintf("memories[%d]: %s", i, validationMessage(err)))
+		}
+		memories[i] = prepared
+	}
+	// Score only once every memory is known to be valid
+	for i, memory := range memories {
+		filled, err := c.fillImportance(ctx, memory)
+		if err != nil {
+			return nil, err
+		}
+		memories[i] = filled
+	}
+	params.Memories = memories
+	key := c.idempotencyKey(params.IdempotencyKey)
+
 	var response BatchCreateResponse
-	err := c.makeRequest(ctx, "POST", "/memories/batch", params, &response, false)
+	err := c.makeRequestWithOptions(ctx, "POST", "/memories/batch", params, &response, idempotencyOptions(key))
 	if err != nil {
 		return nil, err
 	}
+	for _, agentID := range createAgentIDs(params.Memories) {
+		c.invalidateAgentAggregates(agentID)
+	}
 	return response.IDs, nil
 }
 
+// createAgentIDs returns the distinct agents of the memories being created
+func createAgentIDs(memories []CreateMemoryParams) []string {
+	seen := make(map[string]bool, len(memories))
+	var agentIDs []string
+	for _, memory := range memories {
+		if !seen[memory.AgentID] {
+			seen[memory.AgentID] = true
+			agentIDs = append(agentIDs, memory.AgentID)
+		}
+	}
+	return agentIDs
+}
+
+// ValidateBatch checks every memory of a batch create with the rules
+// BatchAddMemories applies, without sending anything, and reports each
+// memory that would be rejected
+func (c *Client) ValidateBatch(params BatchCreateMemoryParams) BatchValidationResult {
+	result := BatchValidationResult{Failed: make(map[int]error)}
+	for i, memory := range params.Memories {
+		if _, err := c.prepareCreateParams(memory); err != nil {
+			result.Failed[i] = err
+		}
+	}
+	return result
+}
+
+// BatchDeleteMemories deletes multiple memories in one request. Partial
+// failures do not fail the call; IDs that could not be deleted are reported
+// in the result's Failed map.
+func (c *Client) BatchDeleteMemories(ctx context.Context, memoryIDs []string) (BatchDeleteResult, error) {
+	params := map[string]interface{}{
+		"ids": memoryIDs,
+	}
+	var response BatchDeleteResponse
+	err := c.makeRequest(ctx, "POST", "/memories/batch/delete", params, &response, false)
+	if err != nil {
+		return BatchDeleteResult{}, err
+	}
+	for _, id := range response.Deleted {
+		c.invalidateMemory(id, "")
+	}
+	return BatchDeleteResult{
+		Deleted: response.Deleted,
+		Failed:  batchItemErrors(response.Failed),
+	}, nil
+}
+
+// DeleteMemoriesByFilter deletes all of an agent's memories matching the
+// filters of query in one request and returns how many were deleted. At
+// least one filter must be set, so an agent's memories cannot all be
+// deleted by accident; text and vector queries, Limit, and paging fields
+// are ignored.
+func (c *Client) DeleteMemoriesByFilter(ctx context.Context, query SearchQuery) (int, error) {
+	if query.AgentID == "" {
+		return 0, NewValidationError("agent ID is required")
+	}
+	if len(query.AgentIDs) > 0 {
+		return 0, NewValidationError("agent IDs are not supported when deleting by filter")
+	}
+	if !query.hasFilters() {
+		return 0, NewValidationError("at least one filter is required to delete memories by filter")
+	}
+	if query.MinImportance != nil {
+		if err := validateImportance("min importance", *query.MinImportance); err != nil {
+			return 0, err
+		}
+	}
+	if err := query.validateTimeRanges(); err != nil {
+		return 0, err
+	}
+	if err := query.validateTags(); err != nil {
+		return 0, err
+	}
+	if err := validateFilters(query.AdvancedFilters); err != nil {
+		return 0, err
+	}
+
+	filter := query.wireQuery()
+	filter.TextQuery, filter.VectorQuery, filter.HybridWeight = nil, nil, nil
+	filter.Limit, filter.Cursor, filter.IncludeTotal, filter.SnapshotID = 0, "", false, nil
+	filter.SortBy, filter.SortOrder, filter.Highlight = "", "", false
+
+	var response DeleteByFilterResponse
+	err := c.makeRequest(ctx, "POST", "/memories/delete", filter, &response, false)
+	if err != nil {
+		return 0, err
+	}
+	c.invalidateAgent(query.AgentID)
+	return response.Deleted, nil
+}
+
+// BatchUpdateMemories applies multiple updates in one request. Every update
+// is validated before anything is sent. Partial failures do not fail the
+// call; IDs that could not be updated are reported in the result's Failed map.
+func (c *Client) BatchUpdateMemories(ctx context.Context, updates []BatchUpdateItem) (BatchUpdateResult, error) {
+	for i, update := range updates {
+		if update.ID == "" {
+			return BatchUpdateResult{}, NewValidationError(fmt.Sprintf("update %d: memory ID is required", i))
+		}
+		if err := update.Params.Validate(); err != nil {
+			return BatchUpdateResult{}, NewValidationError(fmt.Sprintf("update %d: %s", i, validationMessage(err)))
+		}
+	}
+
+	params := map[string]interface{}{
+		"updates": updates,
+	}
+	var response BatchUpdateResponse
+	err := c.makeRequest(ctx, "POST", "/memories/batch/update", params, &response, false)
+	if err != nil {
+		return BatchUpdateResult{}, err
+	}
+	for _, id := range response.Updated {
+		c.invalidateMemory(id, "")
+	}
+	return BatchUpdateResult{
+		Updated: response.Updated,
+		Failed:  batchItemErrors(response.Failed),
+	}, nil
+}
+
 // GetMemoryStats retrieves memory statistics for an agent
 func (c *Client) GetMemoryStats(ctx context.Context, agentID string) (*MemoryStats, error) {
 	var stats MemoryStats
@@ -282,10 +1220,147 @@ func (c *Client) GetMemoryStats(ctx context.Context, agentID string) (*MemorySta
 	return &stats, nil
 }
 
+// GetMemoryStatsFiltered retrieves memory statistics computed over the
+// subset of an agent's memories selected by query, e.g. only episodic
+// memories created in the last 30 days. Statistics are cached with the
+// agent's other entries and dropped by writes to its memories; a query
+// without filters shares the cache entry of GetMemoryStats. With a custom
+// Config.Cache, whose keys cannot be enumerated for eviction, filtered
+// statistics are not cached.
+func (c *Client) GetMemoryStatsFiltered(ctx context.Context, query StatsQuery) (*MemoryStats, error) {
+	if query.AgentID == "" {
+		return nil, NewValidationError("agent ID is required")
+	}
+	if err := validateMemoryType(query.MemoryType); err != nil {
+		return nil, err
+	}
+	if query.MinImportance != nil {
+		if err := validateImportance("min importance", *query.MinImportance); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateTimeRange("created", query.CreatedAfter, query.CreatedBefore); err != nil {
+		return nil, err
+	}
+
+	queryParams, err := countQueryParams(SearchQuery{
+		AgentID:       query.AgentID,
+		MemoryType:    query.MemoryType,
+		MinImportance: query.MinImportance,
+		CreatedAfter:  query.CreatedAfter,
+		CreatedBefore: query.CreatedBefore,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var stats MemoryStats
+	useCache := len(queryParams) == 1 || c.config.Cache == nil
+	err = c.makeRequest(ctx, "GET", "/memories/stats", queryParams, &stats, useCache)
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// CountMemories returns the number of an agent's memories matching the
+// filters of query, without fetching them. Text and vector queries, Limit,
+// and paging fields are ignored. Counts are cached with the agent's other
+// entries and dropped by writes to its memories; with a custom Config.Cache,
+// whose keys cannot be enumerated for eviction, they are not cached.
+func (c *Client) CountMemories(ctx context.Context, query SearchQuery) (int, error) {
+	if query.AgentID == "" {
+		return 0, NewValidationError("agent ID is required")
+	}
+	if len(query.AgentIDs) > 0 {
+		return 0, NewValidationError("agent IDs are not supported when counting memories")
+	}
+	if query.MinImportance != nil {
+		if err := validateImportance("min importance", *query.MinImportance); err != nil {
+			return 0, err
+		}
+	}
+	if err := query.validateTimeRanges(); err != nil {
+		return 0, err
+	}
+	if err := query.validateTags(); err != nil {
+		return 0, err
+	}
+	if err := validateFilters(query.AdvancedFilters); err != nil {
+		return 0, err
+	}
+
+	queryParams, err := countQueryParams(query)
+	if err != nil {
+		return 0, err
+	}
+	var response CountResponse
+	err = c.makeRequest(ctx, "GET", "/memories/count", queryParams, &response, c.config.Cache == nil)
+	if err != nil {
+		return 0, err
+	}
+	return response.Count, nil
+}
+
+// countQueryParams encodes the filters of a search query as query
+// parameters; metadata filters and tags are sent as JSON
+func countQueryParams(query SearchQuery) (map[string]interface{}, error) {
+	queryParams := map[string]interface{}{
+		"agent_id": query.AgentID,
+	}
+	if query.MemoryType != nil {
+		queryParams["memory_type"] = string(*query.MemoryType)
+	}
+	if query.UserID != nil {
+		queryParams["user_id"] = *query.UserID
+	}
+	if query.MinImportance != nil {
+		queryParams["min_importance"] = *query.MinImportance
+	}
+	if query.MaxAgeSeconds != nil {
+		queryParams["max_age_seconds"] = *query.MaxAgeSeconds
+	}
+	for name, bound := range map[string]*time.Time{
+		"created_after":  query.CreatedAfter,
+		"created_before": query.CreatedBefore,
+		"updated_after":  query.UpdatedAfter,
+		"updated_before": query.UpdatedBefore,
+	} {
+		if bound != nil {
+			queryParams[name] = bound.Format(time.RFC3339Nano)
+		}
+	}
+	if filters := query.filters(); len(filters) > 0 {
+		filters, err := json.Marshal(filters)
+		if err != nil {
+			return nil, NewValidationError(fmt.Sprintf("invalid advanced filters: %v", err))
+		}
+		queryParams["advanced_filters"] = string(filters)
+	}
+	if len(query.Tags) > 0 {
+		tags, err := json.Marshal(query.Tags)
+		if err != nil {
+			return nil, NewValidationError(fmt.Sprintf("invalid tags: %v", err))
+		}
+		queryParams["tags"] = string(tags)
+		if query.TagMatchMode != "" {
+			queryParams["tag_match_mode"] = string(query.TagMatchMode)
+		}
+	}
+	return queryParams, nil
+}
+
 // HealthCheck checks API health status
 func (c *Client) HealthCheck(ctx context.Context) (*HealthStatus, error) {
+	// A cached result would keep the health gate closed after recovery
+	return c.healthCheck(ctx, !c.config.GateOnHealth)
+}
+
+// healthCheck checks API health status, optionally from the cache, and
+// records the outcome for the health gate
+func (c *Client) healthCheck(ctx context.Context, useCache bool) (*HealthStatus, error) {
 	var health HealthStatus
-	err := c.makeRequest(ctx, "GET", "/health", nil, &health, true)
+	err := c.makeRequest(ctx, "GET", "/health", nil, &health, useCache)
+	c.recordHealth(&health, err)
 	if err != nil {
 		return nil, err
 	}
@@ -302,16 +1377,31 @@ func (c *Client) GetMetrics(ctx context.Context) (*SystemMetrics, error) {
 	return &metrics, nil
 }
 
-// ClearCache clears the client's cache
+// ClearCache clears the client's cache, including a custom Config.Cache,
+// and the ETags stored for conditional requests
 func (c *Client) ClearCache() {
+	if c.config.Cache != nil {
+		c.config.Cache.Clear()
+	}
+
+	c.etags.clear()
+
 	c.cacheMutex.Lock()
 	defer c.cacheMutex.Unlock()
-	c.cache = make(map[string]*cacheEntry)
+	c.cache = newLRUCache(c.config.MaxCacheEntries)
+	c.partitions = make(map[string]*lruCache)
+	c.cacheAgents = make(map[string]string)
 }
 
 // GetConfig returns the client's configuration (with masked API key)
 func (c *Client) GetConfig() *Config {
 	config := c.config.Clone()
 	config.APIKey = "***" // Mask API key for security
+	if config.OAuth2 != nil {
+		config.OAuth2.ClientSecret = "***"
+	}
+	if proxyURL, err := url.Parse(config.ProxyURL); err == nil && config.ProxyURL != "" {
+		config.ProxyURL = proxyURL.Redacted()
+	}
 	return config
 }
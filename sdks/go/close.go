@@ -0,0 +1,53 @@
+package agentmem
+
+import "context"
+
+// Close shuts the client down for services that defer it: it stops
+// subscriptions and offline flushers and waits for them to return, flushes
+// the offline queue, and closes idle connections. Starting background work
+// afterwards fails with ErrClientClosed; other requests still work but
+// open new connections. Close returns the error of the final flush, if
+// any, and does nothing when called again.
+func (c *Client) Close() error {
+	c.closeMu.Lock()
+	select {
+	case <-c.closed:
+		c.closeMu.Unlock()
+		return nil
+	default:
+	}
+	close(c.closed)
+	c.closeMu.Unlock()
+	c.background.Wait()
+
+	var err error
+	if c.config.OfflineStore != nil && c.PendingCount() > 0 {
+		_, err = c.FlushOfflineQueue(context.Background())
+	}
+	c.httpClient.GetClient().CloseIdleConnections()
+	return err
+}
+
+// backgroundContext derives the context of background work from ctx, so
+// that the work also stops when the client is closed. The caller must call
+// cancel and c.background.Done once the work has returned.
+func (c *Client) backgroundContext(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	c.closeMu.Lock()
+	defer c.closeMu.Unlock()
+	select {
+	case <-c.closed:
+		return nil, nil, ErrClientClosed
+	default:
+	}
+	c.background.Add(1)
+
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-c.closed:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel, nil
+}
@@ -0,0 +1,26 @@
+package agentmem
+
+import "encoding/json"
+
+// marshalJSON encodes v with the configured JSONMarshal, or encoding/json
+func (c *Client) marshalJSON(v interface{}) ([]byte, error) {
+	if c.config.JSONMarshal != nil {
+		return c.config.JSONMarshal(v)
+	}
+	return json.Marshal(v)
+}
+
+// unmarshalJSON decodes data into v with the configured JSONUnmarshal, or
+// encoding/json. With StrictMemoryTypes, data holding an unknown memory
+// type is rejected first, since decoding would map it to MemoryTypeUntyped.
+func (c *Client) unmarshalJSON(data []byte, v interface{}) error {
+	if c.config.StrictMemoryTypes {
+		if err := checkMemoryTypes(data); err != nil {
+			return err
+		}
+	}
+	if c.config.JSONUnmarshal != nil {
+		return c.config.JSONUnmarshal(data, v)
+	}
+	return json.Unmarshal(data, v)
+}
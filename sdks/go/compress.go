@@ -0,0 +1,34 @@
+package agentmem
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+)
+
+// compressBody encodes a request body as JSON and gzips it when compression
+// is enabled and the encoded body exceeds CompressionThreshold. Bodies are
+// returned unchanged, for resty to encode, when nothing is compressed.
+func (c *Client) compressBody(body interface{}) (interface{}, bool, error) {
+	if !c.config.EnableCompression {
+		return body, false, nil
+	}
+
+	encoded, err := c.marshalJSON(body)
+	if err != nil {
+		return nil, false, NewValidationError(fmt.Sprintf("invalid request body: %v", err))
+	}
+	if len(encoded) <= c.config.CompressionThreshold {
+		return encoded, false, nil
+	}
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(encoded); err != nil {
+		return nil, false, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, false, err
+	}
+	return buf.Bytes(), true, nil
+}
@@ -0,0 +1,82 @@
+package agentmem
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// memoryHandler answers every request with a memory whose content has the
+// given size, gzipped when useGzip is set and the client accepts gzip, and
+// counts the gzipped responses
+func memoryHandler(t *testing.T, contentSize int, useGzip bool, gzipped *int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := json.Marshal(Memory{ID: "mem_1", AgentID: "agent", Content: strings.Repeat("a", contentSize)})
+		if err != nil {
+			t.Errorf("encode response: %v", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if !useGzip || !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			w.Write(body)
+			return
+		}
+		var compressed bytes.Buffer
+		gz := gzip.NewWriter(&compressed)
+		gz.Write(body)
+		gz.Close()
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(compressed.Bytes())
+		atomic.AddInt32(gzipped, 1)
+	})
+}
+
+func TestGzipResponsesAndMaxResponseBytes(t *testing.T) {
+	tests := []struct {
+		name             string
+		gzip             bool
+		contentSize      int
+		maxResponseBytes int
+		wantTooLarge     bool
+	}{
+		{name: "gzip decoded", gzip: true, contentSize: 4096},
+		{name: "gzip within limit", gzip: true, contentSize: 4096, maxResponseBytes: 8192},
+		// Compresses to about 1 KiB, far below the limit, but decompresses past it
+		{name: "gzip bomb", gzip: true, contentSize: 1 << 20, maxResponseBytes: 64 << 10, wantTooLarge: true},
+		{name: "plain within limit", contentSize: 4096, maxResponseBytes: 8192},
+		{name: "plain over limit", contentSize: 1 << 20, maxResponseBytes: 64 << 10, wantTooLarge: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gzipped int32
+			client, _ := newServerClient(t, memoryHandler(t, tt.contentSize, tt.gzip, &gzipped), func(config *Config) {
+				config.MaxResponseBytes = tt.maxResponseBytes
+				config.MaxRetries = 0
+			})
+
+			memory, err := client.GetMemory(context.Background(), "mem_1")
+			if got := atomic.LoadInt32(&gzipped) > 0; got != tt.gzip {
+				t.Errorf("response gzipped = %v, want %v", got, tt.gzip)
+			}
+			if tt.wantTooLarge {
+				var tooLarge *ResponseTooLargeError
+				if !errors.As(err, &tooLarge) {
+					t.Fatalf("error = %T %v, want *ResponseTooLargeError", err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetMemory: %v", err)
+			}
+			if len(memory.Content) != tt.contentSize {
+				t.Errorf("len(Content) = %d, want %d", len(memory.Content), tt.contentSize)
+			}
+		})
+	}
+}
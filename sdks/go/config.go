@@ -2,10 +2,15 @@ package agentmem
 
 import (
 	"fmt"
+	"math"
+	"net/http"
 	"net/url"
 	"os"
 	"strconv"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // DefaultConfig returns the default configuration
@@ -19,8 +24,22 @@ func DefaultConfig() *Config {
 		EnableCompression: true,
 		EnableCaching:     true,
 		CacheTTL:          5 * time.Minute,
+		MaxCacheEntries:   1000,
 		EnableLogging:     false,
 		CustomHeaders:     make(map[string]string),
+		RequestIDHeader:   "X-Request-ID",
+		HealthGateTTL:     10 * time.Second,
+		RateLimitBurst:    1,
+
+		CacheMaxEntriesPerAgent: 100,
+		CircuitBreakerCooldown:  30 * time.Second,
+		CompressionThreshold:    1024,
+		MaxIdleConns:            100,
+		MaxIdleConnsPerHost:     100,
+		IdleConnTimeout:         90 * time.Second,
+		DialTimeout:             30 * time.Second,
+		TLSHandshakeTimeout:     10 * time.Second,
+		MaxSearchResults:        defaultMaxSearchResults,
 	}
 }
 
@@ -100,10 +119,19 @@ func NewConfig(apiKey string) *Config {
 
 // Validate validates the configuration
 func (c *Config) Validate() error {
-	if c.APIKey == "" {
+	if c.APIKey == "" && c.OAuth2 == nil {
 		return fmt.Errorf("API key is required")
 	}
 	
+	if c.OAuth2 != nil {
+		if c.OAuth2.TokenURL == "" || c.OAuth2.ClientID == "" || c.OAuth2.ClientSecret == "" {
+			return fmt.Errorf("OAuth2 token URL, client ID, and client secret are required")
+		}
+		if _, err := url.Parse(c.OAuth2.TokenURL); err != nil {
+			return fmt.Errorf("invalid OAuth2 token URL format: %w", err)
+		}
+	}
+	
 	if c.BaseURL == "" {
 		return fmt.Errorf("base URL is required")
 	}
@@ -112,6 +140,26 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("timeout must be positive")
 	}
 	
+	if c.MaxSearchResults < 0 {
+		return fmt.Errorf("max search results must be non-negative")
+	}
+	
+	if c.StreamTimeout < 0 {
+		return fmt.Errorf("stream timeout must be non-negative")
+	}
+	
+	if c.MaxIdleConns < 0 || c.MaxIdleConnsPerHost < 0 || c.IdleConnTimeout < 0 {
+		return fmt.Errorf("connection pool settings must be non-negative")
+	}
+	
+	if c.DialTimeout < 0 || c.TLSHandshakeTimeout < 0 || c.ResponseHeaderTimeout < 0 {
+		return fmt.Errorf("dial, TLS handshake, and response header timeouts must be non-negative")
+	}
+	
+	if c.MaxResponseBytes < 0 {
+		return fmt.Errorf("max response bytes must be non-negative")
+	}
+	
 	if c.MaxRetries < 0 {
 		return fmt.Errorf("max retries must be non-negative")
 	}
@@ -120,15 +168,77 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("retry delay must be non-negative")
 	}
 	
+	if c.MaxRetryDelay < 0 {
+		return fmt.Errorf("max retry delay must be non-negative")
+	}
+	
+	switch c.Backoff {
+	case BackoffDefault, BackoffFixed, BackoffExponential, BackoffExponentialJitter:
+	default:
+		return fmt.Errorf("unknown backoff strategy %q", c.Backoff)
+	}
+	
+	if c.CompressionThreshold < 0 {
+		return fmt.Errorf("compression threshold must be non-negative")
+	}
+	
 	if c.CacheTTL <= 0 {
 		return fmt.Errorf("cache TTL must be positive")
 	}
 	
+	if c.RateLimit < 0 {
+		return fmt.Errorf("rate limit must be non-negative")
+	}
+	
+	if c.RateLimit > 0 && c.RateLimitBurst <= 0 {
+		return fmt.Errorf("rate limit burst must be positive when rate limiting")
+	}
+	
+	if c.CircuitBreakerThreshold < 0 {
+		return fmt.Errorf("circuit breaker threshold must be non-negative")
+	}
+	
+	if c.CircuitBreakerThreshold > 0 && c.CircuitBreakerCooldown <= 0 {
+		return fmt.Errorf("circuit breaker cooldown must be positive when the circuit breaker is enabled")
+	}
+	
+	for memoryType, importance := range c.DefaultImportanceByType {
+		if importance < 0 || importance > 1 || math.IsNaN(importance) {
+			return fmt.Errorf("default importance for memory type %q must be between 0.0 and 1.0, got %v", memoryType, importance)
+		}
+	}
+	
+	if c.RetryBudget < 0 || c.RetryBudget > 1 || math.IsNaN(c.RetryBudget) {
+		return fmt.Errorf("retry budget must be between 0 and 1")
+	}
+	
+	if c.GateOnHealth && c.HealthGateTTL <= 0 {
+		return fmt.Errorf("health gate TTL must be positive when gating on health")
+	}
+	
+	if c.MaxCacheEntries < 0 {
+		return fmt.Errorf("max cache entries must be non-negative")
+	}
+	
+	if c.CachePartitionByAgent && c.CacheMaxEntriesPerAgent <= 0 {
+		return fmt.Errorf("cache max entries per agent must be positive when partitioning by agent")
+	}
+	
 	// Validate URL format
 	if _, err := url.Parse(c.BaseURL); err != nil {
 		return fmt.Errorf("invalid base URL format: %w", err)
 	}
 	
+	if c.ProxyURL != "" {
+		proxyURL, err := url.Parse(c.ProxyURL)
+		if err != nil {
+			return fmt.Errorf("invalid proxy URL format: %w", err)
+		}
+		if proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return fmt.Errorf("proxy URL must include a scheme and host")
+		}
+	}
+	
 	return nil
 }
 
@@ -137,6 +247,31 @@ func (c *Config) GetAPIBaseURL() string {
 	return fmt.Sprintf("%s/api/%s", c.BaseURL, c.APIVersion)
 }
 
+// GetMaxRetryDelay returns the effective cap on the backoff between retries
+func (c *Config) GetMaxRetryDelay() time.Duration {
+	if c.MaxRetryDelay > 0 {
+		return c.MaxRetryDelay
+	}
+	return c.RetryDelay * 10
+}
+
+// GetMaxSearchResults returns the effective cap on the matches fetched by a
+// search with Limit 0
+func (c *Config) GetMaxSearchResults() int {
+	if c.MaxSearchResults > 0 {
+		return c.MaxSearchResults
+	}
+	return defaultMaxSearchResults
+}
+
+// GetBackoff returns the effective retry backoff strategy
+func (c *Config) GetBackoff() BackoffStrategy {
+	if c.Backoff == BackoffDefault && c.FullJitter {
+		return BackoffExponentialJitter
+	}
+	return c.Backoff
+}
+
 // GetDefaultHeaders returns default headers for requests
 func (c *Config) GetDefaultHeaders() map[string]string {
 	headers := map[string]string{
@@ -145,8 +280,13 @@ func (c *Config) GetDefaultHeaders() map[string]string {
 		"User-Agent":    "agentmem-go/6.0.0",
 	}
 	
+	// OAuth2 bearer tokens are set per request
+	if c.OAuth2 != nil {
+		delete(headers, "Authorization")
+	}
+	
 	if c.EnableCompression {
-		headers["Accept-Encoding"] = "gzip, deflate"
+		headers["Accept-Encoding"] = "gzip"
 	}
 	
 	// Add custom headers
@@ -167,6 +307,23 @@ func (c *Config) Clone() *Config {
 		clone.CustomHeaders[key] = value
 	}
 	
+	if c.DefaultImportanceByType != nil {
+		clone.DefaultImportanceByType = make(map[MemoryType]float64, len(c.DefaultImportanceByType))
+		for memoryType, importance := range c.DefaultImportanceByType {
+			clone.DefaultImportanceByType[memoryType] = importance
+		}
+	}
+	
+	// Copy hook slices so appending to the clone's hooks cannot affect c
+	clone.RequestHooks = append(([]func(*http.Request))(nil), c.RequestHooks...)
+	clone.ResponseHooks = append(([]func(*http.Response, error))(nil), c.ResponseHooks...)
+	
+	if c.OAuth2 != nil {
+		oauth2 := *c.OAuth2
+		oauth2.Scopes = append([]string(nil), c.OAuth2.Scopes...)
+		clone.OAuth2 = &oauth2
+	}
+	
 	return &clone
 }
 
@@ -177,6 +334,21 @@ func (c *Config) WithAPIKey(apiKey string) *Config {
 	return clone
 }
 
+// WithOAuth2 returns a new config that authenticates with bearer tokens
+// obtained through the OAuth2 client-credentials grant instead of the API
+// key. Tokens are fetched with the configured HTTPClient, cached, and
+// refreshed before they expire.
+func (c *Config) WithOAuth2(tokenURL, clientID, clientSecret string, scopes []string) *Config {
+	clone := c.Clone()
+	clone.OAuth2 = &OAuth2Config{
+		TokenURL:     tokenURL,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Scopes:       append([]string(nil), scopes...),
+	}
+	return clone
+}
+
 // WithBaseURL returns a new config with the specified base URL
 func (c *Config) WithBaseURL(baseURL string) *Config {
 	clone := c.Clone()
@@ -191,6 +363,65 @@ func (c *Config) WithTimeout(timeout time.Duration) *Config {
 	return clone
 }
 
+// WithStreamTimeout returns a new config with the specified streaming timeout
+func (c *Config) WithStreamTimeout(timeout time.Duration) *Config {
+	clone := c.Clone()
+	clone.StreamTimeout = timeout
+	return clone
+}
+
+// WithHTTPClient returns a new config that sends requests through the
+// specified HTTP client
+func (c *Config) WithHTTPClient(httpClient *http.Client) *Config {
+	clone := c.Clone()
+	clone.HTTPClient = httpClient
+	return clone
+}
+
+// WithMaxSearchResults returns a new config with the specified cap on the
+// matches fetched by searches with Limit 0
+func (c *Config) WithMaxSearchResults(maxResults int) *Config {
+	clone := c.Clone()
+	clone.MaxSearchResults = maxResults
+	return clone
+}
+
+// WithConnectionPool returns a new config with the specified keep-alive
+// connection pool settings
+func (c *Config) WithConnectionPool(maxIdleConns, maxIdleConnsPerHost int, idleConnTimeout time.Duration) *Config {
+	clone := c.Clone()
+	clone.MaxIdleConns = maxIdleConns
+	clone.MaxIdleConnsPerHost = maxIdleConnsPerHost
+	clone.IdleConnTimeout = idleConnTimeout
+	return clone
+}
+
+// WithPhaseTimeouts returns a new config with the specified timeouts for
+// opening a connection, the TLS handshake, and waiting for response headers
+func (c *Config) WithPhaseTimeouts(dial, tlsHandshake, responseHeader time.Duration) *Config {
+	clone := c.Clone()
+	clone.DialTimeout = dial
+	clone.TLSHandshakeTimeout = tlsHandshake
+	clone.ResponseHeaderTimeout = responseHeader
+	return clone
+}
+
+// WithMaxResponseBytes returns a new config that limits response bodies to
+// maxBytes after decompression
+func (c *Config) WithMaxResponseBytes(maxBytes int) *Config {
+	clone := c.Clone()
+	clone.MaxResponseBytes = maxBytes
+	return clone
+}
+
+// WithProxy returns a new config that sends requests through the proxy at
+// proxyURL instead of the one set in the environment
+func (c *Config) WithProxy(proxyURL string) *Config {
+	clone := c.Clone()
+	clone.ProxyURL = proxyURL
+	return clone
+}
+
 // WithRetries returns a new config with the specified retry settings
 func (c *Config) WithRetries(maxRetries int, retryDelay time.Duration) *Config {
 	clone := c.Clone()
@@ -199,6 +430,30 @@ func (c *Config) WithRetries(maxRetries int, retryDelay time.Duration) *Config {
 	return clone
 }
 
+// WithBackoffCap returns a new config with the specified retry backoff cap and jitter mode
+func (c *Config) WithBackoffCap(maxRetryDelay time.Duration, fullJitter bool) *Config {
+	clone := c.Clone()
+	clone.MaxRetryDelay = maxRetryDelay
+	clone.FullJitter = fullJitter
+	return clone
+}
+
+// WithBackoff returns a new config with the specified retry backoff strategy
+func (c *Config) WithBackoff(strategy BackoffStrategy) *Config {
+	clone := c.Clone()
+	clone.Backoff = strategy
+	return clone
+}
+
+// WithCompression returns a new config with the specified compression
+// settings; request bodies larger than threshold bytes are gzipped
+func (c *Config) WithCompression(enabled bool, threshold int) *Config {
+	clone := c.Clone()
+	clone.EnableCompression = enabled
+	clone.CompressionThreshold = threshold
+	return clone
+}
+
 // WithCaching returns a new config with the specified caching settings
 func (c *Config) WithCaching(enabled bool, ttl time.Duration) *Config {
 	clone := c.Clone()
@@ -207,6 +462,189 @@ func (c *Config) WithCaching(enabled bool, ttl time.Duration) *Config {
 	return clone
 }
 
+// WithCache returns a new config that stores cached responses in the
+// specified cache backend
+func (c *Config) WithCache(cache Cache) *Config {
+	clone := c.Clone()
+	clone.Cache = cache
+	return clone
+}
+
+// WithMaxCacheEntries returns a new config with the specified cache size bound
+func (c *Config) WithMaxCacheEntries(maxEntries int) *Config {
+	clone := c.Clone()
+	clone.MaxCacheEntries = maxEntries
+	return clone
+}
+
+// WithTracerProvider returns a new config that traces API calls with the
+// specified OpenTelemetry tracer provider
+func (c *Config) WithTracerProvider(provider trace.TracerProvider) *Config {
+	clone := c.Clone()
+	clone.TracerProvider = provider
+	return clone
+}
+
+// WithPrometheusRegisterer returns a new config that exports client metrics
+// to the specified Prometheus registerer
+func (c *Config) WithPrometheusRegisterer(reg prometheus.Registerer) *Config {
+	clone := c.Clone()
+	clone.PrometheusRegisterer = reg
+	return clone
+}
+
+// WithOfflineQueue returns a new config that queues memories in store when
+// AddMemory fails with a network error. onFlush, which may be nil, is
+// called as FlushOfflineQueue handles each queued memory.
+func (c *Config) WithOfflineQueue(store OfflineStore, onFlush func(localID, serverID string, err error)) *Config {
+	clone := c.Clone()
+	clone.OfflineStore = store
+	clone.OnOfflineFlush = onFlush
+	return clone
+}
+
+// WithEmbedder returns a new config that embeds search EmbedText with the
+// specified embedder
+func (c *Config) WithEmbedder(embedder Embedder) *Config {
+	clone := c.Clone()
+	clone.Embedder = embedder
+	return clone
+}
+
+// WithReranker returns a new config that reranks search results with the
+// specified reranker
+func (c *Config) WithReranker(reranker Reranker) *Config {
+	clone := c.Clone()
+	clone.Reranker = reranker
+	return clone
+}
+
+// WithCachePartitioning returns a new config with per-agent cache partitioning
+func (c *Config) WithCachePartitioning(enabled bool, maxEntriesPerAgent int) *Config {
+	clone := c.Clone()
+	clone.CachePartitionByAgent = enabled
+	clone.CacheMaxEntriesPerAgent = maxEntriesPerAgent
+	return clone
+}
+
+// WithImportanceScaler returns a new config that normalizes importance with scaler
+func (c *Config) WithImportanceScaler(scaler func(float64) float64) *Config {
+	clone := c.Clone()
+	clone.ImportanceScaler = scaler
+	return clone
+}
+
+// WithImportanceScorer returns a new config that scores the importance of
+// memories added without one with scorer
+func (c *Config) WithImportanceScorer(scorer ImportanceScorer) *Config {
+	clone := c.Clone()
+	clone.ImportanceScorer = scorer
+	return clone
+}
+
+// WithDefaultImportanceByType returns a new config that assigns memories
+// added without an importance the default for their memory type
+func (c *Config) WithDefaultImportanceByType(defaults map[MemoryType]float64) *Config {
+	clone := c.Clone()
+	clone.DefaultImportanceByType = make(map[MemoryType]float64, len(defaults))
+	for memoryType, importance := range defaults {
+		clone.DefaultImportanceByType[memoryType] = importance
+	}
+	return clone
+}
+
+// WithJSONLibrary returns a new config that encodes and decodes JSON with
+// marshal and unmarshal instead of encoding/json. Either may be nil to keep
+// encoding/json for that direction. Both must accept the same struct tags
+// as encoding/json.
+func (c *Config) WithJSONLibrary(marshal func(v interface{}) ([]byte, error), unmarshal func(data []byte, v interface{}) error) *Config {
+	clone := c.Clone()
+	clone.JSONMarshal = marshal
+	clone.JSONUnmarshal = unmarshal
+	return clone
+}
+
+// WithStrictMemoryTypes returns a new config that fails decoding responses
+// holding an unknown memory type instead of decoding it as
+// MemoryTypeUntyped
+func (c *Config) WithStrictMemoryTypes(strict bool) *Config {
+	clone := c.Clone()
+	clone.StrictMemoryTypes = strict
+	return clone
+}
+
+// ScaleImportance returns an importance scaler that maps the source range
+// [min, max] linearly onto [0, 1]. Values outside the source range scale
+// outside [0, 1] and are rejected when the memory is created.
+func ScaleImportance(min, max float64) func(float64) float64 {
+	return func(importance float64) float64 {
+		if max <= min {
+			return math.NaN()
+		}
+		return (importance - min) / (max - min)
+	}
+}
+
+// WithRequestIDGenerator returns a new config that generates request
+// correlation IDs with generator and sends them in the named header
+func (c *Config) WithRequestIDGenerator(header string, generator func() string) *Config {
+	clone := c.Clone()
+	clone.RequestIDHeader = header
+	clone.RequestIDGenerator = generator
+	return clone
+}
+
+// WithConditionalRequests returns a new config that enables or disables
+// ETag revalidation of memories read with GetMemory
+func (c *Config) WithConditionalRequests(enabled bool) *Config {
+	clone := c.Clone()
+	clone.ConditionalRequests = enabled
+	return clone
+}
+
+// WithAutoIdempotencyKeys returns a new config that enables or disables
+// generated idempotency keys for memory creation
+func (c *Config) WithAutoIdempotencyKeys(enabled bool) *Config {
+	clone := c.Clone()
+	clone.AutoIdempotencyKeys = enabled
+	return clone
+}
+
+// WithRateLimit returns a new config that limits requests to rps per second
+// with bursts of up to burst requests
+func (c *Config) WithRateLimit(rps float64, burst int) *Config {
+	clone := c.Clone()
+	clone.RateLimit = rps
+	clone.RateLimitBurst = burst
+	return clone
+}
+
+// WithRetryBudget returns a new config that allows at most ratio retries
+// per request across the client, e.g. 0.1 for 10%
+func (c *Config) WithRetryBudget(ratio float64) *Config {
+	clone := c.Clone()
+	clone.RetryBudget = ratio
+	return clone
+}
+
+// WithCircuitBreaker returns a new config that fails requests fast for
+// cooldown after threshold consecutive server or network failures
+func (c *Config) WithCircuitBreaker(threshold int, cooldown time.Duration) *Config {
+	clone := c.Clone()
+	clone.CircuitBreakerThreshold = threshold
+	clone.CircuitBreakerCooldown = cooldown
+	return clone
+}
+
+// WithHealthGate returns a new config that fails requests fast while the
+// backend is reported unhealthy
+func (c *Config) WithHealthGate(enabled bool, ttl time.Duration) *Config {
+	clone := c.Clone()
+	clone.GateOnHealth = enabled
+	clone.HealthGateTTL = ttl
+	return clone
+}
+
 // WithLogging returns a new config with logging enabled/disabled
 func (c *Config) WithLogging(enabled bool) *Config {
 	clone := c.Clone()
@@ -214,6 +652,14 @@ func (c *Config) WithLogging(enabled bool) *Config {
 	return clone
 }
 
+// WithLogger returns a new config that enables logging to the specified logger
+func (c *Config) WithLogger(logger Logger) *Config {
+	clone := c.Clone()
+	clone.EnableLogging = true
+	clone.Logger = logger
+	return clone
+}
+
 // WithCustomHeaders returns a new config with additional custom headers
 func (c *Config) WithCustomHeaders(headers map[string]string) *Config {
 	clone := c.Clone()
@@ -222,3 +668,17 @@ func (c *Config) WithCustomHeaders(headers map[string]string) *Config {
 	}
 	return clone
 }
+
+// WithRequestHook returns a new config with an additional request hook
+func (c *Config) WithRequestHook(hook func(*http.Request)) *Config {
+	clone := c.Clone()
+	clone.RequestHooks = append(clone.RequestHooks, hook)
+	return clone
+}
+
+// WithResponseHook returns a new config with an additional response hook
+func (c *Config) WithResponseHook(hook func(*http.Response, error)) *Config {
+	clone := c.Clone()
+	clone.ResponseHooks = append(clone.ResponseHooks, hook)
+	return clone
+}
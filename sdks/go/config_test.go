@@ -0,0 +1,104 @@
+package agentmem
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestNewClientCopiesConfig mutates the config passed to NewClient while
+// the client is in use; run with -race to catch shared state
+func TestNewClientCopiesConfig(t *testing.T) {
+	var mu sync.Mutex
+	var badRequests []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-api-key" || r.Header.Get("X-Team") != "search" {
+			mu.Lock()
+			badRequests = append(badRequests, fmt.Sprintf("%s %q", r.Header.Get("Authorization"), r.Header.Get("X-Team")))
+			mu.Unlock()
+		}
+		writeServerJSON(w, http.StatusOK, Memory{ID: "mem_1", AgentID: "agent"})
+	})
+
+	config := NewConfig("test-api-key").WithHTTPClient(NewTestHTTPClient(handler))
+	config.CustomHeaders["X-Team"] = "search"
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			config.APIKey = fmt.Sprintf("other-key-%d", i)
+			config.CustomHeaders["X-Team"] = fmt.Sprintf("team-%d", i)
+			config.Timeout = time.Duration(i) * time.Millisecond
+			config.EnableCaching = i%2 == 0
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetMemory(context.Background(), "mem_1"); err != nil {
+				t.Errorf("GetMemory: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(badRequests) > 0 {
+		t.Errorf("requests used the mutated config: %v", badRequests)
+	}
+	if got := client.GetConfig().Timeout; got != 30*time.Second {
+		t.Errorf("client Timeout = %v, want the original 30s", got)
+	}
+}
+
+func TestConfigValidateMaxSearchResults(t *testing.T) {
+	// A struct literal written before MaxSearchResults existed
+	literal := func(maxSearchResults int) *Config {
+		return &Config{
+			APIKey:           "test-api-key",
+			BaseURL:          "https://api.agentmem.dev",
+			APIVersion:       "v1",
+			Timeout:          30 * time.Second,
+			CacheTTL:         5 * time.Minute,
+			MaxSearchResults: maxSearchResults,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		value   int
+		want    int
+		wantErr bool
+	}{
+		{name: "unset uses the default", value: 0, want: defaultMaxSearchResults},
+		{name: "explicit", value: 50, want: 50},
+		{name: "negative", value: -1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := literal(tt.value)
+			_, err := NewClient(config)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("NewClient succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			if got := config.GetMaxSearchResults(); got != tt.want {
+				t.Errorf("GetMaxSearchResults() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package agentmem
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// ApplyDecay asks the server to recompute the importance of an agent's
+// memories, halving it for every HalfLife elapsed since each memory was last
+// accessed. Memories of the agent cached by this client are invalidated.
+func (c *Client) ApplyDecay(ctx context.Context, params DecayParams) (DecayResult, error) {
+	if err := params.Validate(); err != nil {
+		return DecayResult{}, err
+	}
+
+	request := decayRequest{
+		AgentID:           params.AgentID,
+		HalfLifeSeconds:   params.HalfLife.Seconds(),
+		MinImportance:     params.MinImportance,
+		DeletionThreshold: params.DeletionThreshold,
+	}
+	var result DecayResult
+	err := c.makeRequest(ctx, "POST", "/memories/decay", request, &result, false)
+	if err != nil {
+		return DecayResult{}, err
+	}
+	c.invalidateAgent(params.AgentID)
+	return result, nil
+}
+
+// decayRequest is the wire format of DecayParams
+type decayRequest struct {
+	AgentID           string   `json:"agent_id"`
+	HalfLifeSeconds   float64  `json:"half_life_seconds"`
+	MinImportance     *float64 `json:"min_importance,omitempty"`
+	DeletionThreshold *float64 `json:"deletion_threshold,omitempty"`
+}
+
+// Validate checks the params before they are sent: an agent ID and a
+// positive half-life are required, and the floor and threshold, when set,
+// must be within [0, 1]
+func (p DecayParams) Validate() error {
+	if p.AgentID == "" {
+		return NewValidationError("agent ID is required")
+	}
+	if p.HalfLife <= 0 {
+		return NewValidationError("half-life must be positive")
+	}
+	if p.MinImportance != nil {
+		if err := validateImportance("min importance", *p.MinImportance); err != nil {
+			return err
+		}
+	}
+	if p.DeletionThreshold != nil {
+		if err := validateImportance("deletion threshold", *p.DeletionThreshold); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// invalidateAgent drops the cached entries belonging to an agent, including
+// searches spanning several agents that include it. A custom
+// Cache cannot be enumerated, so only the agent's stats are dropped there.
+func (c *Client) invalidateAgent(agentID string) {
+	if !c.config.EnableCaching {
+		return
+	}
+	statsKey := c.getCacheKey("GET", "/memories/stats", map[string]interface{}{"agent_id": agentID})
+	if c.config.Cache != nil {
+		c.config.Cache.Delete(statsKey)
+		return
+	}
+
+	encodedID, _ := json.Marshal(agentID)
+	agentParam := `"agent_id":` + string(encodedID)
+	agentListParam := `"agent_ids":[`
+
+	c.cacheMutex.Lock()
+	defer c.cacheMutex.Unlock()
+
+	var keys []string
+	for key, owner := range c.cacheAgents {
+		if owner == agentID {
+			keys = append(keys, key)
+		}
+	}
+	for key := range c.cache.items {
+		if c.cachedMemoryAgentID(key) == agentID || strings.Contains(key, agentParam) ||
+			(strings.Contains(key, agentListParam) && strings.Contains(key, string(encodedID))) {
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range keys {
+		c.removeCacheKey(key)
+	}
+	c.removeCacheKey(statsKey)
+}
@@ -0,0 +1,41 @@
+package agentmem
+
+import (
+	"context"
+	"math"
+)
+
+// AddMemoryDedup adds a memory unless the agent already has one similar to
+// it. The agent's memories are searched for the content, by vector search
+// on its embedding when an Embedder is configured and by text search
+// otherwise, restricted to the memory type when one is set. If the best
+// match scores at least threshold, within (0, 1], its ID is returned with
+// duplicate set and nothing is created; otherwise the memory is added as
+// with AddMemory.
+func (c *Client) AddMemoryDedup(ctx context.Context, params CreateMemoryParams, threshold float64) (id string, duplicate bool, err error) {
+	if threshold <= 0 || threshold > 1 || math.IsNaN(threshold) {
+		return "", false, NewValidationError("dedupe threshold must be greater than 0.0 and at most 1.0")
+	}
+	if _, err := c.prepareCreateParams(params); err != nil {
+		return "", false, err
+	}
+
+	query := SearchQuery{AgentID: params.AgentID, MemoryType: params.MemoryType, Limit: 1}
+	if c.config.Embedder != nil {
+		query.EmbedText = params.Content
+	} else {
+		content := params.Content
+		query.TextQuery = &content
+	}
+	results, err := c.SearchMemories(ctx, query)
+	if err != nil {
+		return "", false, err
+	}
+	if len(results) > 0 && results[0].Score >= threshold {
+		c.logger.Debugf("Memory for agent %s matches existing memory %s with score %.3f", params.AgentID, results[0].Memory.ID, results[0].Score)
+		return results[0].Memory.ID, true, nil
+	}
+
+	id, err = c.AddMemory(ctx, params)
+	return id, false, err
+}
@@ -0,0 +1,37 @@
+package agentmem
+
+import (
+	"context"
+)
+
+// Embedder turns text into an embedding vector, so searches can set
+// SearchQuery.EmbedText instead of a precomputed VectorQuery. The SDK ships
+// no embedding model; wrap whichever provider produced the stored vectors.
+type Embedder interface {
+	Embed(ctx context.Context, text string) ([]float64, error)
+}
+
+// embedQuery fills the vector query from EmbedText using the configured
+// embedder. Queries with a vector query or without EmbedText are returned
+// unchanged.
+func (c *Client) embedQuery(ctx context.Context, query SearchQuery) (SearchQuery, error) {
+	if query.EmbedText == "" || len(query.VectorQuery) > 0 {
+		return query, nil
+	}
+	if c.config.Embedder == nil {
+		return query, NewValidationError("embed text requires an Embedder in the client config")
+	}
+
+	vector, err := c.config.Embedder.Embed(ctx, query.EmbedText)
+	if err != nil {
+		if ctx.Err() != nil {
+			return query, NewCancelledError(ctx.Err())
+		}
+		return query, NewEmbeddingError(err)
+	}
+	if len(vector) == 0 {
+		return query, NewEmbeddingError(errEmptyEmbedding)
+	}
+	query.VectorQuery = vector
+	return query, nil
+}
@@ -0,0 +1,144 @@
+package agentmem
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+)
+
+// EmbeddingEncoding selects how GetEmbeddings transfers embedding vectors
+type EmbeddingEncoding string
+
+const (
+	// EmbeddingEncodingFloat transfers each vector as a JSON array of numbers
+	EmbeddingEncodingFloat EmbeddingEncoding = "float"
+	// EmbeddingEncodingBase64 transfers each vector as the base64 of its
+	// components as little-endian float32, a fraction of the size of the
+	// JSON numbers at float32 precision
+	EmbeddingEncodingBase64 EmbeddingEncoding = "base64"
+)
+
+// EmbeddingsOptions configures GetEmbeddings and EachEmbedding
+type EmbeddingsOptions struct {
+	// Encoding selects the transfer encoding (default: EmbeddingEncodingFloat)
+	Encoding EmbeddingEncoding
+	// PageSize is the number of embeddings fetched per request
+	// (default: 0, the server's page size)
+	PageSize int
+}
+
+// embeddingsPage is one page of an agent's embeddings. Each embedding is
+// kept raw until it is decoded according to the requested encoding.
+type embeddingsPage struct {
+	Embeddings []struct {
+		ID        string          `json:"id"`
+		Embedding json.RawMessage `json:"embedding"`
+	} `json:"embeddings"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	SnapshotID string `json:"snapshot_id,omitempty"`
+}
+
+// GetEmbeddings returns the embedding vector of each of an agent's
+// memories, keyed by memory ID, without their content or metadata. Memories
+// without an embedding are left out. Use EachEmbedding to process large
+// sets without holding them in memory.
+func (c *Client) GetEmbeddings(ctx context.Context, agentID string, opts EmbeddingsOptions) (map[string][]float64, error) {
+	embeddings := make(map[string][]float64)
+	err := c.EachEmbedding(ctx, agentID, opts, func(memoryID string, embedding []float64) error {
+		embeddings[memoryID] = embedding
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings, nil
+}
+
+// EachEmbedding fetches an agent's embeddings page by page and calls fn
+// with each memory ID and embedding vector. An error returned by fn stops
+// the iteration and is returned as is.
+func (c *Client) EachEmbedding(ctx context.Context, agentID string, opts EmbeddingsOptions, fn func(memoryID string, embedding []float64) error) error {
+	if agentID == "" {
+		return NewValidationError("agent ID is required")
+	}
+	if opts.PageSize < 0 {
+		return NewValidationError("page size must be non-negative")
+	}
+	encoding := opts.Encoding
+	switch encoding {
+	case "":
+		encoding = EmbeddingEncodingFloat
+	case EmbeddingEncodingFloat, EmbeddingEncodingBase64:
+	default:
+		return NewValidationError(fmt.Sprintf("unknown embedding encoding %q", encoding))
+	}
+
+	queryParams := map[string]interface{}{
+		"agent_id": agentID,
+		"encoding": string(encoding),
+	}
+	if opts.PageSize > 0 {
+		queryParams["page_size"] = opts.PageSize
+	}
+	for {
+		var page embeddingsPage
+		if err := c.makeRequest(ctx, "GET", "/memories/embeddings", queryParams, &page, false); err != nil {
+			return err
+		}
+		for _, item := range page.Embeddings {
+			embedding, err := decodeEmbedding(item.Embedding, encoding)
+			if err != nil {
+				return NewDecodeError(http.StatusOK, fmt.Errorf("embedding of memory %s: %w", item.ID, err))
+			}
+			if err := fn(item.ID, embedding); err != nil {
+				return err
+			}
+		}
+		if page.NextCursor == "" {
+			return nil
+		}
+		queryParams["cursor"] = page.NextCursor
+		if page.SnapshotID != "" {
+			queryParams["snapshot_id"] = page.SnapshotID
+		}
+	}
+}
+
+// decodeEmbedding decodes one vector sent with the given encoding
+func decodeEmbedding(raw json.RawMessage, encoding EmbeddingEncoding) ([]float64, error) {
+	if encoding == EmbeddingEncodingFloat {
+		var embedding []float64
+		err := json.Unmarshal(raw, &embedding)
+		return embedding, err
+	}
+
+	var encoded string
+	if err := json.Unmarshal(raw, &encoded); err != nil {
+		return nil, err
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(data)%4 != 0 {
+		return nil, fmt.Errorf("%d bytes is not a whole number of float32 values", len(data))
+	}
+	embedding := make([]float64, len(data)/4)
+	for i := range embedding {
+		embedding[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:])))
+	}
+	return embedding, nil
+}
+
+// encodeEmbedding encodes a vector as EmbeddingEncodingBase64
+func encodeEmbedding(embedding []float64) string {
+	data := make([]byte, len(embedding)*4)
+	for i, value := range embedding {
+		binary.LittleEndian.PutUint32(data[i*4:], math.Float32bits(float32(value)))
+	}
+	return base64.StdEncoding.EncodeToString(data)
+}
@@ -1,7 +1,9 @@
 package agentmem
 
 import (
+	"errors"
 	"fmt"
+	"time"
 )
 
 // AgentMemError represents a base error from AgentMem API
@@ -9,13 +11,43 @@ type AgentMemError struct {
 	Message    string
 	StatusCode int
 	Code       string
+	// RequestID is the correlation ID of the failed request, as reported by
+	// the server when its response carries one
+	RequestID string
 }
 
 func (e *AgentMemError) Error() string {
+	details := fmt.Sprintf("status: %d", e.StatusCode)
+	if e.RequestID != "" {
+		details += ", request_id: " + e.RequestID
+	}
 	if e.Code != "" {
-		return fmt.Sprintf("AgentMem error [%s]: %s (status: %d)", e.Code, e.Message, e.StatusCode)
+		return fmt.Sprintf("AgentMem error [%s]: %s (%s)", e.Code, e.Message, details)
 	}
-	return fmt.Sprintf("AgentMem error: %s (status: %d)", e.Message, e.StatusCode)
+	return fmt.Sprintf("AgentMem error: %s (%s)", e.Message, details)
+}
+
+// base returns the underlying AgentMemError; it is promoted to every
+// error type that embeds *AgentMemError
+func (e *AgentMemError) base() *AgentMemError {
+	return e
+}
+
+// asAgentMemError extracts the AgentMemError from any AgentMem error type
+func asAgentMemError(err error) (*AgentMemError, bool) {
+	var typed interface{ base() *AgentMemError }
+	if errors.As(err, &typed) {
+		return typed.base(), true
+	}
+	return nil, false
+}
+
+// withRequestID stamps a request ID onto an AgentMem error that lacks one
+func withRequestID(err error, requestID string) error {
+	if apiErr, ok := asAgentMemError(err); ok && apiErr.RequestID == "" {
+		apiErr.RequestID = requestID
+	}
+	return err
 }
 
 // AuthenticationError represents authentication failures
@@ -56,6 +88,48 @@ func NewValidationError(message string) *ValidationError {
 	}
 }
 
+// BatchValidationError represents a dry-run batch create in which some
+// memories failed validation. It unwraps to a *ValidationError.
+type BatchValidationError struct {
+	*ValidationError
+	Result BatchValidationResult
+}
+
+// NewBatchValidationError creates a new batch validation error from the
+// result of validating a batch of total memories
+func NewBatchValidationError(result BatchValidationResult, total int) *BatchValidationError {
+	return &BatchValidationError{
+		ValidationError: NewValidationError(fmt.Sprintf("%d of %d memories failed validation", len(result.Failed), total)),
+		Result:          result,
+	}
+}
+
+// Unwrap returns the validation error summarizing the failures
+func (e *BatchValidationError) Unwrap() error {
+	return e.ValidationError
+}
+
+// BatchAddError represents a concurrent add in which some memories were not
+// created
+type BatchAddError struct {
+	*AgentMemError
+	// Failed maps the index of each memory that was not created to its error
+	Failed map[int]error
+}
+
+// NewBatchAddError creates a new batch add error for the failed memories
+// out of total
+func NewBatchAddError(failed map[int]error, total int) *BatchAddError {
+	return &BatchAddError{
+		AgentMemError: &AgentMemError{
+			Message:    fmt.Sprintf("%d of %d memories could not be created", len(failed), total),
+			StatusCode: 0,
+			Code:       "BATCH_ADD_ERROR",
+		},
+		Failed: failed,
+	}
+}
+
 // NetworkError represents network communication errors
 type NetworkError struct {
 	*AgentMemError
@@ -97,6 +171,9 @@ func NewNotFoundError(message string) *NotFoundError {
 // RateLimitError represents rate limiting errors
 type RateLimitError struct {
 	*AgentMemError
+	// RetryAfter is the wait requested by the server's Retry-After header,
+	// or zero when it sent none
+	RetryAfter time.Duration
 }
 
 // NewRateLimitError creates a new rate limit error
@@ -132,6 +209,190 @@ func NewServerError(message string) *ServerError {
 	}
 }
 
+// DecodeError represents a successful response whose body could not be decoded
+type DecodeError struct {
+	*AgentMemError
+	Err error
+}
+
+// NewDecodeError creates a new decode error
+func NewDecodeError(statusCode int, err error) *DecodeError {
+	return &DecodeError{
+		AgentMemError: &AgentMemError{
+			Message:    fmt.Sprintf("Failed to decode response: %v", err),
+			StatusCode: statusCode,
+			Code:       "DECODE_ERROR",
+		},
+		Err: err,
+	}
+}
+
+// Unwrap returns the underlying decoding error
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// ResponseTooLargeError represents a response whose decompressed body
+// exceeded Config.MaxResponseBytes; the body was not read past the limit
+type ResponseTooLargeError struct {
+	*AgentMemError
+}
+
+// NewResponseTooLargeError creates a new response too large error
+func NewResponseTooLargeError() *ResponseTooLargeError {
+	return &ResponseTooLargeError{
+		AgentMemError: &AgentMemError{
+			Message:    "Response body exceeds MaxResponseBytes",
+			StatusCode: 0,
+			Code:       "RESPONSE_TOO_LARGE",
+		},
+	}
+}
+
+// CancelledError represents a request stopped because its context was
+// cancelled or its deadline passed, as opposed to a network failure.
+// It unwraps to context.Canceled or context.DeadlineExceeded.
+type CancelledError struct {
+	*AgentMemError
+	Err error
+}
+
+// NewCancelledError creates a new cancelled error from a context error
+func NewCancelledError(err error) *CancelledError {
+	return &CancelledError{
+		AgentMemError: &AgentMemError{
+			Message:    fmt.Sprintf("Request cancelled: %v", err),
+			StatusCode: 0,
+			Code:       "CANCELLED_ERROR",
+		},
+		Err: err,
+	}
+}
+
+// Unwrap returns the underlying context error
+func (e *CancelledError) Unwrap() error {
+	return e.Err
+}
+
+// ErrSearchTruncated is returned alongside the results fetched so far when
+// a search for all matches (Limit 0) reaches Config.MaxSearchResults
+var ErrSearchTruncated = errors.New("search results truncated at MaxSearchResults")
+
+// ErrClientClosed is returned when background work, such as a
+// subscription, is started on a client after Close
+var ErrClientClosed = errors.New("client is closed")
+
+// errEmptyEmbedding is the cause of an EmbeddingError for an empty vector
+var errEmptyEmbedding = errors.New("embedder returned an empty vector")
+
+// EmbeddingError represents a failure of the configured Embedder to embed
+// a query's EmbedText. It unwraps to the embedder's error.
+type EmbeddingError struct {
+	*AgentMemError
+	Err error
+}
+
+// NewEmbeddingError creates a new embedding error from an embedder error
+func NewEmbeddingError(err error) *EmbeddingError {
+	return &EmbeddingError{
+		AgentMemError: &AgentMemError{
+			Message:    fmt.Sprintf("Failed to embed query text: %v", err),
+			StatusCode: 0,
+			Code:       "EMBEDDING_ERROR",
+		},
+		Err: err,
+	}
+}
+
+// Unwrap returns the embedder's error
+func (e *EmbeddingError) Unwrap() error {
+	return e.Err
+}
+
+// RerankError represents a failure of the configured Reranker. It unwraps
+// to the reranker's error.
+type RerankError struct {
+	*AgentMemError
+	Err error
+}
+
+// NewRerankError creates a new rerank error from a reranker error
+func NewRerankError(err error) *RerankError {
+	return &RerankError{
+		AgentMemError: &AgentMemError{
+			Message:    fmt.Sprintf("Failed to rerank search results: %v", err),
+			StatusCode: 0,
+			Code:       "RERANK_ERROR",
+		},
+		Err: err,
+	}
+}
+
+// Unwrap returns the reranker's error
+func (e *RerankError) Unwrap() error {
+	return e.Err
+}
+
+// ScoringError represents a failure of the configured ImportanceScorer,
+// or a score outside [0, 1]. It unwraps to the scorer's error.
+type ScoringError struct {
+	*AgentMemError
+	Err error
+}
+
+// NewScoringError creates a new scoring error from a scorer error
+func NewScoringError(err error) *ScoringError {
+	return &ScoringError{
+		AgentMemError: &AgentMemError{
+			Message:    fmt.Sprintf("Failed to score memory importance: %v", err),
+			StatusCode: 0,
+			Code:       "SCORING_ERROR",
+		},
+		Err: err,
+	}
+}
+
+// Unwrap returns the scorer's error
+func (e *ScoringError) Unwrap() error {
+	return e.Err
+}
+
+// ImportRecordError reports why one record of an import was not created
+type ImportRecordError struct {
+	// Record is the record's line number in NDJSON input, or its 1-based
+	// index in a JSON array
+	Record int
+	Err    error
+}
+
+// Error returns the record position and the reason it failed
+func (e *ImportRecordError) Error() string {
+	return fmt.Sprintf("record %d: %v", e.Record, e.Err)
+}
+
+// Unwrap returns the error that failed the record
+func (e *ImportRecordError) Unwrap() error {
+	return e.Err
+}
+
+// validationMessage returns the message of a validation error, or the
+// error text for any other error
+func validationMessage(err error) string {
+	if apiErr, ok := asAgentMemError(err); ok {
+		return apiErr.Message
+	}
+	return err.Error()
+}
+
+// batchItemErrors converts per-item batch failures to typed errors
+func batchItemErrors(failed map[string]BatchItemError) map[string]error {
+	errs := make(map[string]error, len(failed))
+	for id, itemErr := range failed {
+		errs[id] = handleHTTPError(itemErr.StatusCode, itemErr.Error)
+	}
+	return errs
+}
+
 // handleHTTPError converts HTTP status codes to appropriate error types
 func handleHTTPError(statusCode int, message string) error {
 	switch statusCode {
@@ -0,0 +1,79 @@
+package agentmem
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestContextCancellationReturnsCancelledError(t *testing.T) {
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	client, _ := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}), nil)
+
+	tests := []struct {
+		name string
+		ctx  func() (context.Context, context.CancelFunc)
+		want error
+	}{
+		{
+			name: "cancelled",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(20*time.Millisecond, cancel)
+				return ctx, cancel
+			},
+			want: context.Canceled,
+		},
+		{
+			name: "deadline exceeded",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 20*time.Millisecond)
+			},
+			want: context.DeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			_, err := client.GetMemory(ctx, "mem_1")
+			var cancelled *CancelledError
+			if !errors.As(err, &cancelled) {
+				t.Fatalf("error = %T %v, want *CancelledError", err, err)
+			}
+			if !errors.Is(err, tt.want) {
+				t.Errorf("error %v does not unwrap to %v", err, tt.want)
+			}
+			var networkErr *NetworkError
+			if errors.As(err, &networkErr) {
+				t.Errorf("cancellation reported as NetworkError: %v", err)
+			}
+		})
+	}
+}
+
+func TestNetworkFailureReturnsNetworkError(t *testing.T) {
+	client, server := newServerClient(t, http.NotFoundHandler(), func(config *Config) {
+		config.MaxRetries = 0
+	})
+	server.Close()
+
+	_, err := client.GetMemory(context.Background(), "mem_1")
+	var networkErr *NetworkError
+	if !errors.As(err, &networkErr) {
+		t.Fatalf("error = %T %v, want *NetworkError", err, err)
+	}
+	var cancelled *CancelledError
+	if errors.As(err, &cancelled) || errors.Is(err, context.Canceled) {
+		t.Errorf("network failure reported as cancellation: %v", err)
+	}
+}
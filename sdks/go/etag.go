@@ -0,0 +1,125 @@
+package agentmem
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// etagEntry is a memory together with the ETag it was served with
+type etagEntry struct {
+	etag   string
+	memory *Memory
+}
+
+// etagStore remembers the ETag and content of memories read by GetMemory,
+// keyed by memory ID, for conditional requests. It outlives response cache
+// entries, so that an expired entry can be revalidated instead of fetched.
+type etagStore struct {
+	mu      sync.Mutex
+	entries *lruCache
+}
+
+// newETagStore returns an ETag store holding at most maxEntries memories,
+// or nil when conditional requests are disabled
+func newETagStore(enabled bool, maxEntries int) *etagStore {
+	if !enabled {
+		return nil
+	}
+	return &etagStore{entries: newLRUCache(maxEntries)}
+}
+
+// get returns the stored ETag and memory for a memory ID
+func (s *etagStore) get(memoryID string) (etagEntry, bool) {
+	if s == nil {
+		return etagEntry{}, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, found := s.entries.get(memoryID)
+	if !found {
+		return etagEntry{}, false
+	}
+	return entry.data.(etagEntry), true
+}
+
+// set stores a copy of memory with the ETag it was served with
+func (s *etagStore) set(memoryID, etag string, memory *Memory) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries.set(memoryID, &cacheEntry{data: etagEntry{etag: etag, memory: memory.Clone()}})
+}
+
+// remove forgets the ETag of a memory
+func (s *etagStore) remove(memoryID string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries.remove(memoryID)
+}
+
+// clear forgets all ETags
+func (s *etagStore) clear() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = newLRUCache(s.entries.maxEntries)
+}
+
+// getMemoryConditional reads a memory like GetMemory, revalidating a
+// memory read before with If-None-Match when it is not in the cache. A
+// 304 Not Modified response returns the stored memory and caches it again.
+func (c *Client) getMemoryConditional(ctx context.Context, memoryID string) (memory *Memory, err error) {
+	endpoint := fmt.Sprintf("/memories/%s", memoryID)
+	cacheKey := c.getCacheKey("GET", endpoint, nil)
+	if c.config.EnableCaching {
+		if cachedData, found := c.getFromCache(cacheKey); found {
+			var cached Memory
+			if c.decodeCached(cachedData, &cached) == nil {
+				return &cached, nil
+			}
+		}
+	}
+
+	ctx, span := c.startSpan(ctx, "GET", endpoint, nil)
+	defer func() { endSpan(span, err) }()
+
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, NewCancelledError(ctxErr)
+	}
+	if err := c.checkHealthGate(endpoint, RequestOptions{}); err != nil {
+		return nil, err
+	}
+
+	var opts RequestOptions
+	stored, revalidate := c.etags.get(memoryID)
+	if revalidate {
+		opts.Headers = map[string]string{"If-None-Match": stored.etag}
+	}
+	resp, requestID, err := c.send(ctx, span, "GET", endpoint, nil, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if revalidate && resp.StatusCode() == http.StatusNotModified {
+		memory = stored.memory.Clone()
+	} else {
+		memory = &Memory{}
+		if err := c.unmarshalJSON(resp.Body(), memory); err != nil {
+			return nil, withRequestID(NewDecodeError(resp.StatusCode(), err), requestID)
+		}
+		if etag := resp.Header().Get("ETag"); etag != "" {
+			c.etags.set(memoryID, etag, memory)
+		}
+	}
+	c.setCache(cacheKey, memory, memory.AgentID, 0)
+	return memory, nil
+}
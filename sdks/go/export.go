@@ -0,0 +1,30 @@
+package agentmem
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ExportMemories writes all memories of an agent to w as NDJSON, one Memory
+// object per line, and returns how many were written. Memories are fetched
+// page by page, so the export is never held in memory as a whole. The
+// output can be loaded back with ImportMemories, which ignores the fields
+// assigned by the server.
+func (c *Client) ExportMemories(ctx context.Context, agentID string, w io.Writer) (int, error) {
+	if agentID == "" {
+		return 0, NewValidationError("agent ID is required")
+	}
+
+	encoder := json.NewEncoder(w)
+	it := c.IterateMemories(ctx, agentID)
+	exported := 0
+	for memory, ok := it.Next(); ok; memory, ok = it.Next() {
+		if err := encoder.Encode(memory); err != nil {
+			return exported, fmt.Errorf("failed to write export data: %w", err)
+		}
+		exported++
+	}
+	return exported, it.Err()
+}
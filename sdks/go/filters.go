@@ -0,0 +1,168 @@
+package agentmem
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// MetadataFilterBuilder builds metadata filters with per-operator value type
+// checks, so malformed filters are caught before the request is sent.
+//
+//	filters, err := agentmem.NewMetadataFilterBuilder().
+//		Equals("category", "preferences").
+//		In("source", []string{"chat", "email"}).
+//		GreaterThan("priority", 3).
+//		Exists("reviewed_at").
+//		Build()
+//	query.AdvancedFilters = filters
+type MetadataFilterBuilder struct {
+	filters []MetadataFilter
+	err     error
+}
+
+// NewMetadataFilterBuilder creates an empty metadata filter builder
+func NewMetadataFilterBuilder() *MetadataFilterBuilder {
+	return &MetadataFilterBuilder{}
+}
+
+// Equals matches memories whose metadata key equals a string, number, or bool value
+func (b *MetadataFilterBuilder) Equals(key string, value interface{}) *MetadataFilterBuilder {
+	if !isScalarFilterValue(value) {
+		return b.fail(key, FilterOpEq, "value must be a string, number, or bool")
+	}
+	return b.add(key, FilterOpEq, value)
+}
+
+// NotEquals matches memories whose metadata key does not equal a string,
+// number, or bool value
+func (b *MetadataFilterBuilder) NotEquals(key string, value interface{}) *MetadataFilterBuilder {
+	if !isScalarFilterValue(value) {
+		return b.fail(key, FilterOpNe, "value must be a string, number, or bool")
+	}
+	return b.add(key, FilterOpNe, value)
+}
+
+// In matches memories whose metadata key equals any of the given values.
+// values must be a non-empty slice of strings, numbers, or bools.
+func (b *MetadataFilterBuilder) In(key string, values interface{}) *MetadataFilterBuilder {
+	rv := reflect.ValueOf(values)
+	if !rv.IsValid() || (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) {
+		return b.fail(key, FilterOpIn, "values must be a slice")
+	}
+	if rv.Len() == 0 {
+		return b.fail(key, FilterOpIn, "values must not be empty")
+	}
+	items := make([]interface{}, rv.Len())
+	for i := range items {
+		items[i] = rv.Index(i).Interface()
+		if !isScalarFilterValue(items[i]) {
+			return b.fail(key, FilterOpIn, "values must be strings, numbers, or bools")
+		}
+	}
+	return b.add(key, FilterOpIn, items)
+}
+
+// GreaterThan matches memories whose metadata key is greater than a number or time
+func (b *MetadataFilterBuilder) GreaterThan(key string, value interface{}) *MetadataFilterBuilder {
+	if !isOrderedFilterValue(value) {
+		return b.fail(key, FilterOpGt, "value must be a number or time.Time")
+	}
+	return b.add(key, FilterOpGt, value)
+}
+
+// GreaterOrEqual matches memories whose metadata key is at least a number or time
+func (b *MetadataFilterBuilder) GreaterOrEqual(key string, value interface{}) *MetadataFilterBuilder {
+	if !isOrderedFilterValue(value) {
+		return b.fail(key, FilterOpGte, "value must be a number or time.Time")
+	}
+	return b.add(key, FilterOpGte, value)
+}
+
+// LessThan matches memories whose metadata key is less than a number or time
+func (b *MetadataFilterBuilder) LessThan(key string, value interface{}) *MetadataFilterBuilder {
+	if !isOrderedFilterValue(value) {
+		return b.fail(key, FilterOpLt, "value must be a number or time.Time")
+	}
+	return b.add(key, FilterOpLt, value)
+}
+
+// LessOrEqual matches memories whose metadata key is at most a number or time
+func (b *MetadataFilterBuilder) LessOrEqual(key string, value interface{}) *MetadataFilterBuilder {
+	if !isOrderedFilterValue(value) {
+		return b.fail(key, FilterOpLte, "value must be a number or time.Time")
+	}
+	return b.add(key, FilterOpLte, value)
+}
+
+// Contains matches memories whose metadata key is a string containing value
+// as a substring, or a list containing value as an element
+func (b *MetadataFilterBuilder) Contains(key string, value interface{}) *MetadataFilterBuilder {
+	if !isScalarFilterValue(value) {
+		return b.fail(key, FilterOpContains, "value must be a string, number, or bool")
+	}
+	return b.add(key, FilterOpContains, value)
+}
+
+// Exists matches memories that have the metadata key set
+func (b *MetadataFilterBuilder) Exists(key string) *MetadataFilterBuilder {
+	return b.add(key, FilterOpExists, true)
+}
+
+// Build returns the filters, or a ValidationError for the first invalid one
+func (b *MetadataFilterBuilder) Build() ([]MetadataFilter, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	filters := make([]MetadataFilter, len(b.filters))
+	copy(filters, b.filters)
+	return filters, nil
+}
+
+// add appends a filter unless the builder already failed
+func (b *MetadataFilterBuilder) add(key string, op FilterOperator, value interface{}) *MetadataFilterBuilder {
+	if b.err != nil {
+		return b
+	}
+	if key == "" {
+		return b.fail(key, op, "key is required")
+	}
+	b.filters = append(b.filters, MetadataFilter{Field: key, Operator: op, Value: value})
+	return b
+}
+
+// fail records the first validation error
+func (b *MetadataFilterBuilder) fail(key string, op FilterOperator, reason string) *MetadataFilterBuilder {
+	if b.err == nil {
+		b.err = NewValidationError(fmt.Sprintf("invalid %s filter on metadata key %q: %s", op, key, reason))
+	}
+	return b
+}
+
+// isScalarFilterValue reports whether value is a string, number, or bool
+func isScalarFilterValue(value interface{}) bool {
+	switch value.(type) {
+	case string, bool:
+		return true
+	}
+	return isNumericFilterValue(value)
+}
+
+// isOrderedFilterValue reports whether value supports ordering comparisons
+func isOrderedFilterValue(value interface{}) bool {
+	if _, ok := value.(time.Time); ok {
+		return true
+	}
+	return isNumericFilterValue(value)
+}
+
+// isNumericFilterValue reports whether value is an integer or float
+func isNumericFilterValue(value interface{}) bool {
+	switch value.(type) {
+	case int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64,
+		float32, float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package agentmem
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// healthyStatus is the status reported by a healthy backend or service
+const healthyStatus = "healthy"
+
+// Healthy reports whether the backend and every service it reports on are
+// healthy
+func (h *HealthStatus) Healthy() bool {
+	return h.Status == healthyStatus && len(h.UnhealthyServices()) == 0
+}
+
+// IsServiceHealthy reports whether the named service is reported as
+// healthy. Services missing from the report are not considered healthy.
+func (h *HealthStatus) IsServiceHealthy(name string) bool {
+	return h.Services[name] == healthyStatus
+}
+
+// UnhealthyServices returns the names of the services not reported as
+// healthy, e.g. degraded ones, in sorted order
+func (h *HealthStatus) UnhealthyServices() []string {
+	var names []string
+	for name, status := range h.Services {
+		if status != healthyStatus {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WaitForReady blocks until a health check reports the backend as healthy,
+// checking every interval. Health checks bypass the cache. When ctx is done
+// first, the error of the last check is returned, or why the backend was
+// not considered healthy.
+func (c *Client) WaitForReady(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return NewValidationError("interval must be positive")
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastErr error
+	for {
+		health, err := c.healthCheck(ctx, false)
+		var cancelledErr *CancelledError
+		switch {
+		case errors.As(err, &cancelledErr):
+			// Keep the outcome of the last completed check
+		case err != nil:
+			lastErr = err
+		case health.Status == healthyStatus:
+			return nil
+		default:
+			lastErr = NewServerError(fmt.Sprintf("Backend not ready: health status is %q", health.Status))
+		}
+
+		select {
+		case <-ctx.Done():
+			if lastErr == nil {
+				return NewCancelledError(ctx.Err())
+			}
+			return lastErr
+		case <-ticker.C:
+		}
+	}
+}
+
+// healthGate remembers the outcome of the most recent health check
+type healthGate struct {
+	mu        sync.Mutex
+	healthy   bool
+	checkedAt time.Time
+}
+
+// record stores the outcome of a health check
+func (g *healthGate) record(healthy bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.healthy = healthy
+	g.checkedAt = time.Now()
+}
+
+// isDown reports whether a health check within ttl found the backend unhealthy
+func (g *healthGate) isDown(ttl time.Duration) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return !g.checkedAt.IsZero() && !g.healthy && time.Since(g.checkedAt) <= ttl
+}
+
+// recordHealth feeds a HealthCheck outcome into the health gate. Only
+// failures that indicate an unavailable backend count as unhealthy.
+func (c *Client) recordHealth(health *HealthStatus, err error) {
+	if err != nil {
+		var networkErr *NetworkError
+		var serverErr *ServerError
+		if errors.As(err, &networkErr) || errors.As(err, &serverErr) {
+			c.health.record(false)
+		}
+		return
+	}
+	c.health.record(health.Status == healthyStatus)
+}
+
+// checkHealthGate fails fast when GateOnHealth is enabled and the most
+// recent health check found the backend unhealthy. Health checks themselves
+// and requests marked Critical are always let through. The gate relies on
+// HealthCheck being called, e.g. by a periodic readiness probe.
+func (c *Client) checkHealthGate(endpoint string, opts RequestOptions) error {
+	if !c.config.GateOnHealth || opts.Critical || endpoint == "/health" {
+		return nil
+	}
+	if c.health.isDown(c.config.HealthGateTTL) {
+		return NewServerError("Request skipped: the most recent health check reported the backend as unhealthy")
+	}
+	return nil
+}
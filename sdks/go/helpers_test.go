@@ -0,0 +1,99 @@
+package agentmem
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newHandlerClient creates a client that serves every request with handler,
+// with caching disabled and short retry delays unless configure changes them
+func newHandlerClient(t *testing.T, handler http.Handler, configure func(*Config)) *Client {
+	t.Helper()
+	config := NewConfig("test-api-key").WithHTTPClient(NewTestHTTPClient(handler))
+	config.EnableCaching = false
+	config.RetryDelay = time.Millisecond
+	if configure != nil {
+		configure(config)
+	}
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return client
+}
+
+// newServerClient starts an HTTP test server running handler and creates a
+// client for it, with caching disabled and short retry delays unless
+// configure changes them
+func newServerClient(t *testing.T, handler http.Handler, configure func(*Config)) (*Client, *httptest.Server) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	config := NewConfig("test-api-key").WithBaseURL(server.URL)
+	config.EnableCaching = false
+	config.RetryDelay = time.Millisecond
+	if configure != nil {
+		configure(config)
+	}
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return client, server
+}
+
+// recordingLogger is a Logger that keeps every line, prefixed with its level
+type recordingLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *recordingLogger) record(level, format string, v ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, level+" "+fmt.Sprintf(format, v...))
+}
+
+func (l *recordingLogger) Debugf(format string, v ...interface{}) { l.record("DEBUG", format, v...) }
+func (l *recordingLogger) Infof(format string, v ...interface{})  { l.record("INFO", format, v...) }
+func (l *recordingLogger) Warnf(format string, v ...interface{})  { l.record("WARN", format, v...) }
+func (l *recordingLogger) Errorf(format string, v ...interface{}) { l.record("ERROR", format, v...) }
+
+// output returns everything logged so far
+func (l *recordingLogger) output() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return strings.Join(l.lines, "\n")
+}
+
+// countingHandler passes requests on to a handler, counting those that
+// reach it per method and endpoint, the path after the API version
+type countingHandler struct {
+	handler http.Handler
+	counts  sync.Map // "METHOD endpoint" -> *int64
+}
+
+func (h *countingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	endpoint := r.URL.Path
+	if segments := strings.SplitN(strings.TrimPrefix(endpoint, "/"), "/", 3); len(segments) == 3 && segments[0] == "api" {
+		endpoint = "/" + segments[2]
+	}
+	count, _ := h.counts.LoadOrStore(r.Method+" "+endpoint, new(int64))
+	atomic.AddInt64(count.(*int64), 1)
+	h.handler.ServeHTTP(w, r)
+}
+
+// count returns the number of requests served for method and endpoint
+func (h *countingHandler) count(method, endpoint string) int64 {
+	count, ok := h.counts.Load(method + " " + endpoint)
+	if !ok {
+		return 0
+	}
+	return atomic.LoadInt64(count.(*int64))
+}
@@ -0,0 +1,104 @@
+package agentmem
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// highlightContext is the number of characters of context kept on each
+	// side of a match in a client-side highlight
+	highlightContext = 40
+	// maxHighlights bounds the number of client-side highlights per result
+	maxHighlights = 5
+)
+
+// highlightResults fills the Highlights of results the server did not
+// highlight, from the query's TextQuery
+func highlightResults(query SearchQuery, results []SearchResult) {
+	if !query.Highlight || query.TextQuery == nil {
+		return
+	}
+	for i := range results {
+		highlightResult(*query.TextQuery, &results[i])
+	}
+}
+
+// highlightResult fills the Highlights of a result the server did not
+// highlight
+func highlightResult(text string, result *SearchResult) {
+	if result.Highlights == nil {
+		result.Highlights = highlightContent(result.Memory.Content, text)
+	}
+}
+
+// highlightContent returns the fragments of content around case-insensitive
+// matches of text, with up to highlightContext characters of context on
+// each side. Overlapping fragments are merged. When the whole text does not
+// occur, its words are matched individually.
+func highlightContent(content, text string) []string {
+	runes := []rune(content)
+	lower := lowerRunes(runes)
+
+	spans := matchSpans(lower, lowerRunes([]rune(strings.TrimSpace(text))))
+	if len(spans) == 0 {
+		for _, word := range strings.Fields(text) {
+			spans = append(spans, matchSpans(lower, lowerRunes([]rune(word)))...)
+		}
+	}
+	if len(spans) == 0 {
+		return nil
+	}
+
+	// Widen each match by its context, then merge overlapping fragments in
+	// order of position
+	for i := range spans {
+		spans[i][0] = max(spans[i][0]-highlightContext, 0)
+		spans[i][1] = min(spans[i][1]+highlightContext, len(runes))
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+	merged := spans[:1]
+	for _, span := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if span[0] <= last[1] {
+			last[1] = max(last[1], span[1])
+			continue
+		}
+		merged = append(merged, span)
+	}
+
+	highlights := make([]string, 0, min(len(merged), maxHighlights))
+	for _, span := range merged[:min(len(merged), maxHighlights)] {
+		highlights = append(highlights, strings.TrimSpace(string(runes[span[0]:span[1]])))
+	}
+	return highlights
+}
+
+// matchSpans returns the [start, end) rune offsets of the non-overlapping
+// occurrences of needle in haystack
+func matchSpans(haystack, needle []rune) [][2]int {
+	if len(needle) == 0 {
+		return nil
+	}
+	var spans [][2]int
+	for i := 0; i+len(needle) <= len(haystack); {
+		if !slices.Equal(haystack[i:i+len(needle)], needle) {
+			i++
+			continue
+		}
+		spans = append(spans, [2]int{i, i + len(needle)})
+		i += len(needle)
+	}
+	return spans
+}
+
+// lowerRunes lower-cases each rune, keeping offsets aligned with the input
+func lowerRunes(runes []rune) []rune {
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+	return lower
+}
@@ -0,0 +1,30 @@
+package agentmem
+
+import (
+	"context"
+	"fmt"
+)
+
+// GetMemoryHistory retrieves the version history of a memory, oldest version
+// first, with the current version last. The history is cached like other GET
+// responses and invalidated when the memory is updated through this client.
+func (c *Client) GetMemoryHistory(ctx context.Context, memoryID string) ([]MemoryVersion, error) {
+	if memoryID == "" {
+		return nil, NewValidationError("memory ID is required")
+	}
+
+	var response MemoryHistoryResponse
+	err := c.makeRequest(ctx, "GET", fmt.Sprintf("/memories/%s/history", memoryID), nil, &response, true)
+	if err != nil {
+		return nil, err
+	}
+	if response.Versions == nil {
+		response.Versions = []MemoryVersion{}
+	}
+	return response.Versions, nil
+}
+
+// memoryHistoryCacheKey returns the cache key of a memory's history
+func (c *Client) memoryHistoryCacheKey(memoryID string) string {
+	return c.getCacheKey("GET", fmt.Sprintf("/memories/%s/history", memoryID), nil)
+}
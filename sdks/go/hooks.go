@@ -0,0 +1,45 @@
+package agentmem
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// runRequestHooks applies the configured request hooks, in order, to an
+// outgoing HTTP request
+func (c *Client) runRequestHooks(req *http.Request) {
+	for _, hook := range c.config.RequestHooks {
+		hook(req)
+	}
+}
+
+// preRequestHook adapts the request hooks to resty, which calls it right
+// before each attempt is sent. OAuth2 tokens are set here so that retries
+// pick up a refreshed token.
+func (c *Client) preRequestHook(_ *resty.Client, req *http.Request) error {
+	if err := c.authorize(req); err != nil {
+		return err
+	}
+	c.runRequestHooks(req)
+	return nil
+}
+
+// runResponseHooks passes the outcome of a request to the configured
+// response hooks, in order. resty has already read the response body, so
+// the hooks are given a fresh reader over it.
+func (c *Client) runResponseHooks(resp *resty.Response, err error) {
+	if len(c.config.ResponseHooks) == 0 {
+		return
+	}
+	var rawResponse *http.Response
+	if resp != nil && resp.RawResponse != nil {
+		rawResponse = resp.RawResponse
+		rawResponse.Body = io.NopCloser(bytes.NewReader(resp.Body()))
+	}
+	for _, hook := range c.config.ResponseHooks {
+		hook(rawResponse, err)
+	}
+}
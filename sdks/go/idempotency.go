@@ -0,0 +1,25 @@
+package agentmem
+
+// idempotencyKeyHeader carries the idempotency key of a create request
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// idempotencyKey returns the key for one logical create call: key when set,
+// a new random key when AutoIdempotencyKeys is enabled, or "" for none
+func (c *Client) idempotencyKey(key string) string {
+	if key == "" && c.config.AutoIdempotencyKeys {
+		return newUUID()
+	}
+	return key
+}
+
+// idempotencyOptions returns the options of an uncached create request that
+// sends key, if any. The header is set once per call, so every retry of the
+// request carries the same key.
+func idempotencyOptions(key string) RequestOptions {
+	useCache := false
+	opts := RequestOptions{UseCache: &useCache}
+	if key != "" {
+		opts.Headers = map[string]string{idempotencyKeyHeader: key}
+	}
+	return opts
+}
@@ -0,0 +1,276 @@
+package agentmem
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+const (
+	defaultImportBatchSize = 100
+	maxImportLineSize      = 16 * 1024 * 1024
+)
+
+// ImportMemories bulk-creates memories read from r, either as NDJSON with
+// one CreateMemoryParams object per line or as a single JSON array of them.
+// Records are parsed into batches that opts.Concurrency workers submit with
+// BatchAddMemories. Parsing stays at most Concurrency batches ahead of the
+// workers, so NDJSON input is never loaded into memory as a whole.
+//
+// Records that cannot be decoded or fail validation are skipped, as are
+// batches the server rejects; each skipped record is counted as failed and
+// reported in the result's Errors. The import stops at the first error
+// that may be temporary, such as a network or server error, or one that
+// leaves the input unreadable, and returns it along with the counts so far.
+// r is read until the input ends or the import stops at its first error or
+// because ctx is done; ImportMemories returns only once it has stopped
+// reading r, so a read that blocks delays its return.
+func (c *Client) ImportMemories(ctx context.Context, r io.Reader, opts ImportOptions) (ImportResult, error) {
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultImportBatchSize
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	importCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		result   ImportResult
+		firstErr error
+	)
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+	reject := func(records []int, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		result.Failed += len(records)
+		for _, record := range records {
+			result.Errors = append(result.Errors, &ImportRecordError{Record: record, Err: err})
+		}
+	}
+
+	reader := &importReader{
+		client:    c,
+		batchSize: batchSize,
+		batches:   make(chan importBatch, concurrency),
+		reject:    reject,
+	}
+	readerDone := make(chan struct{})
+	go func() {
+		defer close(readerDone)
+		defer close(reader.batches)
+		if err := reader.read(importCtx, r); err != nil {
+			fail(err)
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				var batch importBatch
+				var ok bool
+				select {
+				case <-importCtx.Done():
+					return
+				case batch, ok = <-reader.batches:
+					if !ok {
+						return
+					}
+				}
+
+				ids, err := c.BatchAddMemories(importCtx, BatchCreateMemoryParams{Memories: batch.memories})
+				if err == nil {
+					mu.Lock()
+					result.Created += len(ids)
+					mu.Unlock()
+					continue
+				}
+				reject(batch.records, err)
+				if isTemporaryError(err) {
+					fail(fmt.Errorf("batch starting at record %d: %w", batch.records[0], err))
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	// Workers stop early on failure or cancellation; the reader must stop
+	// too, so it no longer reads r or records failures once this returns
+	cancel()
+	<-readerDone
+
+	mu.Lock()
+	defer mu.Unlock()
+	sort.SliceStable(result.Errors, func(i, j int) bool {
+		return result.Errors[i].Record < result.Errors[j].Record
+	})
+	if firstErr == nil && ctx.Err() != nil {
+		firstErr = NewCancelledError(ctx.Err())
+	}
+	return result, firstErr
+}
+
+// importBatch is a group of parsed records submitted together
+type importBatch struct {
+	// records holds the position of each memory in the input
+	records  []int
+	memories []CreateMemoryParams
+}
+
+// importReader parses import records and groups them into batches
+type importReader struct {
+	client    *Client
+	batchSize int
+	batches   chan importBatch
+	reject    func(records []int, err error)
+
+	batch importBatch
+}
+
+// read parses the records in r and sends them in batches until the input
+// ends or ctx is done
+func (ir *importReader) read(ctx context.Context, r io.Reader) error {
+	br := bufio.NewReader(r)
+	first, err := peekNonSpace(br)
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read import data: %w", err)
+	}
+
+	if first == '[' {
+		err = ir.readArray(ctx, br)
+	} else {
+		err = ir.readLines(ctx, br)
+	}
+	if err != nil || ctx.Err() != nil {
+		return err
+	}
+	if len(ir.batch.memories) > 0 {
+		ir.send(ctx)
+	}
+	return nil
+}
+
+// readLines parses NDJSON records, where a record's position is its line
+// number
+func (ir *importReader) readLines(ctx context.Context, r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxImportLineSize)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		if !ir.add(ctx, line, data) {
+			return nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read import data: %w", err)
+	}
+	return nil
+}
+
+// readArray parses the elements of a JSON array, where a record's position
+// is its 1-based index. A syntax error ends the import, since the decoder
+// cannot resume after it.
+func (ir *importReader) readArray(ctx context.Context, r io.Reader) error {
+	decoder := json.NewDecoder(r)
+	if _, err := decoder.Token(); err != nil {
+		return NewValidationError(fmt.Sprintf("invalid import data: %v", err))
+	}
+
+	index := 0
+	for decoder.More() {
+		index++
+		var data json.RawMessage
+		if err := decoder.Decode(&data); err != nil {
+			return NewValidationError(fmt.Sprintf("record %d: invalid import data: %v", index, err))
+		}
+		if !ir.add(ctx, index, data) {
+			return nil
+		}
+	}
+	if _, err := decoder.Token(); err != nil {
+		return NewValidationError(fmt.Sprintf("invalid import data: %v", err))
+	}
+	return nil
+}
+
+// add decodes and validates one record and queues it for import, reporting
+// it as failed if it is invalid. It returns false once ctx is done.
+func (ir *importReader) add(ctx context.Context, record int, data []byte) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var params CreateMemoryParams
+	if err := json.Unmarshal(data, &params); err != nil {
+		ir.reject([]int{record}, NewValidationError(fmt.Sprintf("invalid memory record: %v", err)))
+		return true
+	}
+	// Validate up front so one bad record does not fail its whole batch.
+	// The original params are queued, as BatchAddMemories prepares them again.
+	if _, err := ir.client.prepareCreateParams(params); err != nil {
+		ir.reject([]int{record}, err)
+		return true
+	}
+
+	ir.batch.records = append(ir.batch.records, record)
+	ir.batch.memories = append(ir.batch.memories, params)
+	if len(ir.batch.memories) == ir.batchSize {
+		return ir.send(ctx)
+	}
+	return true
+}
+
+// send hands the current batch to the workers. It returns false if ctx is
+// done first.
+func (ir *importReader) send(ctx context.Context) bool {
+	select {
+	case ir.batches <- ir.batch:
+		ir.batch = importBatch{}
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// peekNonSpace skips leading whitespace in r and returns the next byte
+// without consuming it
+func peekNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return b, r.UnreadByte()
+	}
+}
@@ -0,0 +1,95 @@
+package agentmem
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// stallingReader returns its first chunk, then blocks in Read until
+// released, and then returns the rest
+type stallingReader struct {
+	first, rest []byte
+	stalled     chan struct{}
+	release     chan struct{}
+	reads       int32
+	active      int32
+}
+
+func (r *stallingReader) Read(p []byte) (int, error) {
+	atomic.AddInt32(&r.active, 1)
+	defer atomic.AddInt32(&r.active, -1)
+	switch atomic.AddInt32(&r.reads, 1) {
+	case 1:
+		return copy(p, r.first), nil
+	case 2:
+		close(r.stalled)
+		<-r.release
+		return copy(p, r.rest), nil
+	default:
+		return 0, io.EOF
+	}
+}
+
+func TestImportMemoriesWaitsForReader(t *testing.T) {
+	served := make(chan struct{}, 1)
+	client := newHandlerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeServerError(w, http.StatusServiceUnavailable, "unavailable")
+		select {
+		case served <- struct{}{}:
+		default:
+		}
+	}), func(config *Config) {
+		config.MaxRetries = 0
+	})
+
+	reader := &stallingReader{
+		first:   []byte(`{"agent_id":"agent","content":"one"}` + "\n"),
+		rest:    []byte(`{"agent_id":"agent","content":"two"}` + "\n" + `{"agent_id":"agent"}` + "\n"),
+		stalled: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+
+	type outcome struct {
+		result ImportResult
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := client.ImportMemories(context.Background(), reader, ImportOptions{BatchSize: 1})
+		done <- outcome{result, err}
+	}()
+
+	<-reader.stalled
+	<-served
+	select {
+	case <-done:
+		t.Fatal("ImportMemories returned while a read of its input was blocked")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(reader.release)
+	got := <-done
+	if active := atomic.LoadInt32(&reader.active); active != 0 {
+		t.Errorf("%d reads still in progress after ImportMemories returned", active)
+	}
+	reads := atomic.LoadInt32(&reader.reads)
+	time.Sleep(20 * time.Millisecond)
+	if after := atomic.LoadInt32(&reader.reads); after != reads {
+		t.Errorf("input read %d more times after ImportMemories returned", after-reads)
+	}
+
+	var serverErr *ServerError
+	if !errors.As(got.err, &serverErr) {
+		t.Errorf("error = %v, want *ServerError", got.err)
+	}
+	// Only the submitted record failed; the reader stopped before it could
+	// reject the invalid record that followed
+	if got.result.Created != 0 || got.result.Failed != 1 || len(got.result.Errors) != 1 || got.result.Errors[0].Record != 1 {
+		t.Errorf("result = %+v, want only record 1 failed", got.result)
+	}
+}
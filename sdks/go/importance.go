@@ -0,0 +1,103 @@
+package agentmem
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Importance levels map to and from the float Importance in these buckets:
+//
+//	ImportanceLow       [0.0, 0.4)  ToFloat: 0.25
+//	ImportanceMedium    [0.4, 0.7)  ToFloat: 0.5
+//	ImportanceHigh      [0.7, 0.9)  ToFloat: 0.8
+//	ImportanceCritical  [0.9, 1.0]  ToFloat: 1.0
+const (
+	importanceMediumFloor   = 0.4
+	importanceHighFloor     = 0.7
+	importanceCriticalFloor = 0.9
+)
+
+// importanceLevelNames holds the name of each level, indexed by level
+var importanceLevelNames = [...]string{
+	ImportanceLow:      "low",
+	ImportanceMedium:   "medium",
+	ImportanceHigh:     "high",
+	ImportanceCritical: "critical",
+}
+
+// IsValid reports whether l is one of the defined importance levels
+func (l ImportanceLevel) IsValid() bool {
+	return l >= ImportanceLow && l <= ImportanceCritical
+}
+
+// ToFloat returns the Importance representing the level, or 0 for an
+// invalid level
+func (l ImportanceLevel) ToFloat() float64 {
+	switch l {
+	case ImportanceLow:
+		return 0.25
+	case ImportanceMedium:
+		return 0.5
+	case ImportanceHigh:
+		return 0.8
+	case ImportanceCritical:
+		return 1.0
+	}
+	return 0
+}
+
+// FloatToImportanceLevel returns the level whose bucket contains
+// importance. Values below 0 are Low and values above 1 are Critical.
+func FloatToImportanceLevel(importance float64) ImportanceLevel {
+	switch {
+	case importance >= importanceCriticalFloor:
+		return ImportanceCritical
+	case importance >= importanceHighFloor:
+		return ImportanceHigh
+	case importance >= importanceMediumFloor:
+		return ImportanceMedium
+	default:
+		return ImportanceLow
+	}
+}
+
+// String returns the level's name, e.g. "high"
+func (l ImportanceLevel) String() string {
+	if !l.IsValid() {
+		return fmt.Sprintf("ImportanceLevel(%d)", int(l))
+	}
+	return importanceLevelNames[l]
+}
+
+// MarshalText encodes the level as its name, so it appears in JSON as a
+// string such as "high"
+func (l ImportanceLevel) MarshalText() ([]byte, error) {
+	if !l.IsValid() {
+		return nil, fmt.Errorf("invalid importance level %d", int(l))
+	}
+	return []byte(importanceLevelNames[l]), nil
+}
+
+// UnmarshalText decodes a level from its name, ignoring case
+func (l *ImportanceLevel) UnmarshalText(text []byte) error {
+	name := strings.ToLower(string(text))
+	for level := ImportanceLow; level <= ImportanceCritical; level++ {
+		if importanceLevelNames[level] == name {
+			*l = level
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown importance level %q", text)
+}
+
+// defaultImportance sets the importance of params without one from
+// DefaultImportanceByType, when its memory type has a default
+func (c *Client) defaultImportance(params CreateMemoryParams) CreateMemoryParams {
+	if params.Importance != nil || params.MemoryType == nil {
+		return params
+	}
+	if importance, ok := c.config.DefaultImportanceByType[*params.MemoryType]; ok {
+		params.Importance = &importance
+	}
+	return params
+}
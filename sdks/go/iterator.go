@@ -0,0 +1,94 @@
+package agentmem
+
+import (
+	"context"
+)
+
+const defaultIteratorPageSize = 100
+
+// searchPageSize is the page size used when a search fetches all matches
+const searchPageSize = 100
+
+// defaultMaxSearchResults caps the matches fetched by a search with Limit 0
+// when Config.MaxSearchResults is not set
+const defaultMaxSearchResults = 10000
+
+// MemoryIterator walks all memories of an agent, fetching pages lazily
+// through ListMemories so at most one page is held in memory. When the
+// server supports snapshots, every page comes from the set of memories
+// present when iteration started, so concurrent writes cause no
+// duplicates or skips.
+//
+//	it := client.IterateMemories(ctx, agentID)
+//	for memory, ok := it.Next(); ok; memory, ok = it.Next() {
+//		// use memory
+//	}
+//	if err := it.Err(); err != nil {
+//		// handle error
+//	}
+type MemoryIterator struct {
+	client  *Client
+	ctx     context.Context
+	agentID string
+
+	page     []Memory
+	pos      int
+	cursor   string
+	snapshot string
+	done     bool
+	err      error
+}
+
+// IterateMemories returns an iterator over all memories of an agent
+func (c *Client) IterateMemories(ctx context.Context, agentID string) *MemoryIterator {
+	return &MemoryIterator{
+		client:  c,
+		ctx:     ctx,
+		agentID: agentID,
+	}
+}
+
+// Next returns the next memory. It returns false once all memories have
+// been returned or an error occurred; check Err to tell the two apart.
+func (it *MemoryIterator) Next() (*Memory, bool) {
+	for it.pos >= len(it.page) {
+		if it.done || it.err != nil {
+			return nil, false
+		}
+		it.fetchPage()
+	}
+
+	memory := &it.page[it.pos]
+	it.pos++
+	return memory, true
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *MemoryIterator) Err() error {
+	return it.err
+}
+
+// fetchPage replaces the buffered page with the next one
+func (it *MemoryIterator) fetchPage() {
+	if err := it.ctx.Err(); err != nil {
+		it.err = NewCancelledError(err)
+		return
+	}
+
+	result, err := it.client.ListMemories(it.ctx, ListMemoriesParams{
+		AgentID:    it.agentID,
+		PageSize:   defaultIteratorPageSize,
+		Cursor:     it.cursor,
+		SnapshotID: it.snapshot,
+	})
+	if err != nil {
+		it.err = err
+		return
+	}
+
+	it.page = result.Memories
+	it.pos = 0
+	it.cursor = result.NextCursor
+	it.snapshot = result.SnapshotID
+	it.done = result.NextCursor == ""
+}
@@ -0,0 +1,83 @@
+package agentmem
+
+import (
+	"context"
+	"fmt"
+)
+
+// LinkMemories creates a directed relation from one memory to another, e.g.
+// linking an episodic event to the semantic fact it supports
+func (c *Client) LinkMemories(ctx context.Context, sourceID, targetID, relationType string) error {
+	if err := validateLink(sourceID, targetID, relationType); err != nil {
+		return err
+	}
+
+	params := LinkParams{
+		TargetID:     targetID,
+		RelationType: relationType,
+	}
+	err := c.makeRequest(ctx, "POST", fmt.Sprintf("/memories/%s/links", sourceID), params, nil, false)
+	if err != nil {
+		return err
+	}
+	c.invalidateRelations(sourceID, targetID)
+	return nil
+}
+
+// GetRelatedMemories retrieves the memories linked to or from a memory, with
+// the relation type and direction of each link
+func (c *Client) GetRelatedMemories(ctx context.Context, memoryID string) ([]RelatedMemory, error) {
+	if memoryID == "" {
+		return nil, NewValidationError("memory ID is required")
+	}
+
+	var response RelatedMemoriesResponse
+	err := c.makeRequest(ctx, "GET", fmt.Sprintf("/memories/%s/related", memoryID), nil, &response, true)
+	if err != nil {
+		return nil, err
+	}
+	if response.Related == nil {
+		response.Related = []RelatedMemory{}
+	}
+	return response.Related, nil
+}
+
+// GetMemoryWithRelations retrieves a memory by ID with its Relations populated
+func (c *Client) GetMemoryWithRelations(ctx context.Context, memoryID string) (*Memory, error) {
+	var memory Memory
+	queryParams := map[string]interface{}{
+		"include_relations": true,
+	}
+	err := c.makeRequest(ctx, "GET", fmt.Sprintf("/memories/%s", memoryID), queryParams, &memory, true)
+	if err != nil {
+		return nil, err
+	}
+	return &memory, nil
+}
+
+// validateLink checks the endpoints and type of a memory link
+func validateLink(sourceID, targetID, relationType string) error {
+	if sourceID == "" || targetID == "" {
+		return NewValidationError("source and target memory IDs are required")
+	}
+	if sourceID == targetID {
+		return NewValidationError("a memory cannot be linked to itself")
+	}
+	if relationType == "" {
+		return NewValidationError("relation type is required")
+	}
+	return nil
+}
+
+// invalidateRelations drops the cached relations of the given memories
+func (c *Client) invalidateRelations(memoryIDs ...string) {
+	var keys []string
+	for _, memoryID := range memoryIDs {
+		endpoint := fmt.Sprintf("/memories/%s", memoryID)
+		keys = append(keys,
+			c.getCacheKey("GET", endpoint+"/related", nil),
+			c.getCacheKey("GET", endpoint, map[string]interface{}{"include_relations": true}),
+		)
+	}
+	c.invalidateCacheKeys(keys...)
+}
@@ -0,0 +1,81 @@
+package agentmem
+
+import "context"
+
+// ListOption configures GetMemoriesByAgent
+type ListOption func(*listOptions)
+
+// listOptions collects the ListOptions of a GetMemoriesByAgent call
+type listOptions struct {
+	params ListMemoriesParams
+	limit  int
+}
+
+// WithType lists only memories of the given type
+func WithType(memoryType MemoryType) ListOption {
+	return func(o *listOptions) {
+		o.params.MemoryType = &memoryType
+	}
+}
+
+// WithMinImportance lists only memories with at least the given importance
+func WithMinImportance(minImportance float64) ListOption {
+	return func(o *listOptions) {
+		o.params.MinImportance = &minImportance
+	}
+}
+
+// WithLimit returns at most limit memories; 0 returns all of them
+func WithLimit(limit int) ListOption {
+	return func(o *listOptions) {
+		o.limit = limit
+	}
+}
+
+// WithSortBy orders the memories by field, highest or newest first
+func WithSortBy(field SortField) ListOption {
+	return func(o *listOptions) {
+		o.params.SortBy = field
+	}
+}
+
+// GetMemoriesByAgent returns an agent's memories, without the need for a
+// search query. Without WithLimit all matching memories are fetched, page by
+// page, and returned together.
+//
+//	memories, err := client.GetMemoriesByAgent(ctx, agentID,
+//		agentmem.WithType(agentmem.MemoryTypeEpisodic),
+//		agentmem.WithSortBy(agentmem.SortByCreatedAt),
+//		agentmem.WithLimit(20))
+func (c *Client) GetMemoriesByAgent(ctx context.Context, agentID string, opts ...ListOption) ([]Memory, error) {
+	var options listOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	params, limit := options.params, options.limit
+	params.AgentID = agentID
+	if limit < 0 {
+		return nil, NewValidationError("limit must be non-negative")
+	}
+
+	memories := []Memory{}
+	for {
+		params.PageSize = defaultIteratorPageSize
+		if remaining := limit - len(memories); limit > 0 && remaining < params.PageSize {
+			params.PageSize = remaining
+		}
+		result, err := c.ListMemories(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		memories = append(memories, result.Memories...)
+		if limit > 0 && len(memories) >= limit {
+			return memories[:limit], nil
+		}
+		if result.NextCursor == "" {
+			return memories, nil
+		}
+		params.Cursor = result.NextCursor
+		params.SnapshotID = result.SnapshotID
+	}
+}
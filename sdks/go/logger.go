@@ -0,0 +1,88 @@
+package agentmem
+
+import (
+	"fmt"
+	"log"
+	"log/slog"
+)
+
+// Logger receives the client's log output: cache hits, retries, and
+// request traces. It is also used as resty's logger.
+type Logger interface {
+	Debugf(format string, v ...interface{})
+	Infof(format string, v ...interface{})
+	Warnf(format string, v ...interface{})
+	Errorf(format string, v ...interface{})
+}
+
+// newLogger returns the logger for a client: the configured Logger, the
+// standard logger when logging is enabled without one, or a logger that
+// discards everything
+func newLogger(config *Config) Logger {
+	if !config.EnableLogging {
+		return nopLogger{}
+	}
+	if config.Logger != nil {
+		return config.Logger
+	}
+	return stdLogger{logger: log.Default()}
+}
+
+// stdLogger writes to a standard library logger
+type stdLogger struct {
+	logger *log.Logger
+}
+
+func (l stdLogger) Debugf(format string, v ...interface{}) {
+	l.logger.Printf("[AgentMem] "+format, v...)
+}
+
+func (l stdLogger) Infof(format string, v ...interface{}) {
+	l.logger.Printf("[AgentMem] "+format, v...)
+}
+
+func (l stdLogger) Warnf(format string, v ...interface{}) {
+	l.logger.Printf("[AgentMem] WARN "+format, v...)
+}
+
+func (l stdLogger) Errorf(format string, v ...interface{}) {
+	l.logger.Printf("[AgentMem] ERROR "+format, v...)
+}
+
+// nopLogger discards all log output
+type nopLogger struct{}
+
+func (nopLogger) Debugf(format string, v ...interface{}) {}
+func (nopLogger) Infof(format string, v ...interface{})  {}
+func (nopLogger) Warnf(format string, v ...interface{})  {}
+func (nopLogger) Errorf(format string, v ...interface{}) {}
+
+// slogLogger adapts a *slog.Logger to Logger
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// NewSlogLogger returns a Logger writing to a structured slog logger at the
+// matching levels. A nil logger uses slog.Default().
+func NewSlogLogger(logger *slog.Logger) Logger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return slogLogger{logger: logger.With("component", "agentmem")}
+}
+
+func (l slogLogger) Debugf(format string, v ...interface{}) {
+	l.logger.Debug(fmt.Sprintf(format, v...))
+}
+
+func (l slogLogger) Infof(format string, v ...interface{}) {
+	l.logger.Info(fmt.Sprintf(format, v...))
+}
+
+func (l slogLogger) Warnf(format string, v ...interface{}) {
+	l.logger.Warn(fmt.Sprintf(format, v...))
+}
+
+func (l slogLogger) Errorf(format string, v ...interface{}) {
+	l.logger.Error(fmt.Sprintf(format, v...))
+}
@@ -0,0 +1,82 @@
+package agentmem
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// IsValid reports whether t is one of the known memory types
+func (t MemoryType) IsValid() bool {
+	switch t {
+	case MemoryTypeEpisodic, MemoryTypeSemantic, MemoryTypeProcedural, MemoryTypeUntyped:
+		return true
+	}
+	return false
+}
+
+// ParseMemoryType returns the memory type named by s, ignoring case and
+// surrounding whitespace
+func ParseMemoryType(s string) (MemoryType, error) {
+	t := MemoryType(strings.ToLower(strings.TrimSpace(s)))
+	if !t.IsValid() {
+		return "", NewValidationError(fmt.Sprintf("unknown memory type %q", s))
+	}
+	return t, nil
+}
+
+// UnmarshalJSON decodes a memory type, mapping unknown values to
+// MemoryTypeUntyped. Clients configured with StrictMemoryTypes reject
+// responses holding unknown values before they are decoded.
+func (t *MemoryType) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*t = MemoryType(s)
+	if !t.IsValid() {
+		*t = MemoryTypeUntyped
+	}
+	return nil
+}
+
+// checkMemoryTypes reports an error if any "memory_type" field in the JSON
+// document data holds an unknown memory type. Malformed JSON is left for
+// the decoder to report.
+func checkMemoryTypes(data []byte) error {
+	var document interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil
+	}
+	return checkMemoryTypeValues(document)
+}
+
+// checkMemoryTypeValues walks a decoded JSON value for unknown memory types
+func checkMemoryTypeValues(value interface{}) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if s, ok := field.(string); ok && key == "memory_type" && !MemoryType(s).IsValid() {
+				return fmt.Errorf("unknown memory type %q", s)
+			}
+			if err := checkMemoryTypeValues(field); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if err := checkMemoryTypeValues(item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateMemoryType checks that a memory type, when set, is known
+func validateMemoryType(t *MemoryType) error {
+	if t != nil && !t.IsValid() {
+		return NewValidationError(fmt.Sprintf("unknown memory type %q", *t))
+	}
+	return nil
+}
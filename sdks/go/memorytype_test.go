@@ -0,0 +1,107 @@
+package agentmem
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestParseMemoryType(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    MemoryType
+		wantErr bool
+	}{
+		{input: "episodic", want: MemoryTypeEpisodic},
+		{input: " Semantic ", want: MemoryTypeSemantic},
+		{input: "PROCEDURAL", want: MemoryTypeProcedural},
+		{input: "untyped", want: MemoryTypeUntyped},
+		{input: "hologram", wantErr: true},
+		{input: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMemoryType(tt.input)
+		if tt.wantErr {
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("ParseMemoryType(%q) error = %v, want *ValidationError", tt.input, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseMemoryType(%q) = %q, %v, want %q", tt.input, got, err, tt.want)
+		}
+	}
+}
+
+func TestMemoryTypeUnmarshalJSONMapsUnknownToUntyped(t *testing.T) {
+	var memory Memory
+	if err := json.Unmarshal([]byte(`{"id":"mem_1","memory_type":"hologram"}`), &memory); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if memory.MemoryType != MemoryTypeUntyped {
+		t.Errorf("MemoryType = %q, want %q", memory.MemoryType, MemoryTypeUntyped)
+	}
+}
+
+func TestStrictMemoryTypesIsPerClient(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"mem_1","agent_id":"agent","memory_type":"hologram"}`))
+	})
+
+	tests := []struct {
+		name   string
+		strict bool
+	}{
+		{name: "lenient", strict: false},
+		{name: "strict", strict: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Clients in parallel subtests must not affect each other
+			t.Parallel()
+			client := newHandlerClient(t, handler, func(config *Config) {
+				config.StrictMemoryTypes = tt.strict
+			})
+			for i := 0; i < 20; i++ {
+				memory, err := client.GetMemory(context.Background(), "mem_1")
+				if tt.strict {
+					var decodeErr *DecodeError
+					if !errors.As(err, &decodeErr) {
+						t.Fatalf("error = %T %v, want *DecodeError", err, err)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("GetMemory: %v", err)
+				}
+				if memory.MemoryType != MemoryTypeUntyped {
+					t.Fatalf("MemoryType = %q, want %q", memory.MemoryType, MemoryTypeUntyped)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckMemoryTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{name: "known", data: `{"memory_type":"episodic"}`},
+		{name: "nested known", data: `{"results":[{"memory":{"memory_type":"semantic"}}]}`},
+		{name: "unknown", data: `{"memory_type":"hologram"}`, wantErr: true},
+		{name: "nested unknown", data: `{"results":[{"memory":{"memory_type":"hologram"}}]}`, wantErr: true},
+		{name: "type names as keys", data: `{"memories_by_type":{"hologram":1}}`},
+		{name: "malformed", data: `{"memory_type":`},
+	}
+	for _, tt := range tests {
+		if err := checkMemoryTypes([]byte(tt.data)); (err != nil) != tt.wantErr {
+			t.Errorf("%s: checkMemoryTypes() = %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,860 @@
+package agentmem
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memoryServerPageSize is the page size of list requests that set none
+const memoryServerPageSize = 100
+
+// unsupportedMemoryRoutes are the /memories/<name> endpoints MemoryServer
+// does not implement, which would otherwise be taken for memory IDs
+var unsupportedMemoryRoutes = map[string]bool{
+	"decay":     true,
+	"delete":    true,
+	"subscribe": true,
+}
+
+// MemoryServer is an in-memory implementation of the AgentMem API for
+// tests, to be used with NewTestClient. It serves the memory CRUD, list,
+// search, batch create/get/delete, tag, stats, count, embeddings, and
+// health endpoints under any /api/<version> prefix.
+//
+// Text search matches the query as a case-insensitive substring of the
+// content. Vector and hybrid search, links, history, decay, and streaming
+// are not implemented and answer 501 Not Implemented.
+type MemoryServer struct {
+	mu       sync.Mutex
+	memories map[string]*Memory
+	// order holds the memory IDs in creation order
+	order []string
+}
+
+// NewMemoryServer creates an empty in-memory server
+func NewMemoryServer() *MemoryServer {
+	return &MemoryServer{memories: make(map[string]*Memory)}
+}
+
+// Memories returns copies of all stored memories in creation order, for
+// assertions in tests
+func (s *MemoryServer) Memories() []Memory {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	memories := make([]Memory, 0, len(s.order))
+	for _, id := range s.order {
+		memories = append(memories, *s.memories[id].Clone())
+	}
+	return memories
+}
+
+// ServeHTTP dispatches an API request to its endpoint
+func (s *MemoryServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(segments) < 3 || segments[0] != "api" {
+		writeServerError(w, http.StatusNotFound, "not found")
+		return
+	}
+	route := segments[2:]
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case len(route) == 1 && route[0] == "health":
+		s.health(w, r)
+	case route[0] != "memories":
+		writeServerError(w, http.StatusNotFound, "not found")
+	case len(route) == 1:
+		s.memoriesRoot(w, r)
+	case len(route) == 2 && route[1] == "search":
+		s.search(w, r)
+	case len(route) == 2 && route[1] == "batch":
+		s.batchCreate(w, r)
+	case len(route) == 3 && route[1] == "batch" && route[2] == "get":
+		s.batchGet(w, r)
+	case len(route) == 3 && route[1] == "batch" && route[2] == "delete":
+		s.batchDelete(w, r)
+	case len(route) == 2 && route[1] == "stats":
+		s.stats(w, r)
+	case len(route) == 2 && route[1] == "count":
+		s.count(w, r)
+	case len(route) == 2 && route[1] == "embeddings":
+		s.embeddings(w, r)
+	case len(route) == 2 && unsupportedMemoryRoutes[route[1]]:
+		writeServerError(w, http.StatusNotImplemented, "not implemented by MemoryServer")
+	case len(route) == 2:
+		s.memory(w, r, route[1])
+	case len(route) == 3 && route[2] == "tags":
+		s.tags(w, r, route[1])
+	default:
+		writeServerError(w, http.StatusNotImplemented, "not implemented by MemoryServer")
+	}
+}
+
+// memoriesRoot creates a memory or lists an agent's memories
+func (s *MemoryServer) memoriesRoot(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		var params CreateMemoryParams
+		if !decodeServerBody(w, r, &params) {
+			return
+		}
+		memory, err := s.create(params)
+		if err != nil {
+			writeServerError(w, http.StatusBadRequest, validationMessage(err))
+			return
+		}
+		writeServerJSON(w, http.StatusCreated, CreateMemoryResponse{ID: memory.ID})
+	case http.MethodGet:
+		s.list(w, r)
+	default:
+		writeServerError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// create validates params and stores a new memory
+func (s *MemoryServer) create(params CreateMemoryParams) (*Memory, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	memory := &Memory{
+		ID:         newUUID(),
+		Content:    params.Content,
+		MemoryType: MemoryTypeUntyped,
+		AgentID:    params.AgentID,
+		UserID:     clonePtr(params.UserID),
+		SessionID:  clonePtr(params.SessionID),
+		Importance: 0.5,
+		Metadata:   cloneMetadata(params.Metadata),
+		Tags:       append([]string(nil), params.Tags...),
+		CreatedAt:  &now,
+		UpdatedAt:  &now,
+	}
+	if params.MemoryType != nil {
+		memory.MemoryType = *params.MemoryType
+	}
+	if params.Importance != nil {
+		memory.Importance = *params.Importance
+	}
+	s.memories[memory.ID] = memory
+	s.order = append(s.order, memory.ID)
+	return memory, nil
+}
+
+// memory gets, updates, or deletes one memory
+func (s *MemoryServer) memory(w http.ResponseWriter, r *http.Request, id string) {
+	memory, ok := s.memories[id]
+	if !ok {
+		writeServerError(w, http.StatusNotFound, fmt.Sprintf("memory %s not found", id))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		now := time.Now().UTC()
+		memory.AccessCount++
+		memory.LastAccessed = &now
+		if r.URL.Query().Get("exclude_embedding") == "true" {
+			projected := *memory
+			projected.Embedding = nil
+			writeServerJSON(w, http.StatusOK, &projected)
+			return
+		}
+		writeServerJSON(w, http.StatusOK, memory)
+	case http.MethodPut:
+		var params UpdateMemoryParams
+		if !decodeServerBody(w, r, &params) {
+			return
+		}
+		if err := params.Validate(); err != nil {
+			writeServerError(w, http.StatusBadRequest, validationMessage(err))
+			return
+		}
+		applyUpdate(memory, params)
+		writeServerJSON(w, http.StatusOK, memory)
+	case http.MethodDelete:
+		s.delete(id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeServerError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// applyUpdate applies the changes in params to memory
+func applyUpdate(memory *Memory, params UpdateMemoryParams) {
+	if params.Content != nil {
+		memory.Content = *params.Content
+	}
+	if params.Importance != nil {
+		memory.Importance = *params.Importance
+	}
+	if params.MemoryType != nil {
+		memory.MemoryType = *params.MemoryType
+	}
+	if params.UserID != nil {
+		memory.UserID = clonePtr(params.UserID)
+	}
+	if params.Metadata != nil {
+		if params.MetadataMergeMode == MetadataMerge {
+			if memory.Metadata == nil {
+				memory.Metadata = make(map[string]interface{})
+			}
+			for key, value := range params.Metadata {
+				if value == nil {
+					delete(memory.Metadata, key)
+				} else {
+					memory.Metadata[key] = cloneValue(value)
+				}
+			}
+		} else {
+			memory.Metadata = cloneMetadata(params.Metadata)
+		}
+	}
+	for _, field := range params.ClearFields {
+		switch field {
+		case ClearImportance:
+			memory.Importance = 0.5
+		case ClearMetadata:
+			memory.Metadata = nil
+		}
+	}
+	now := time.Now().UTC()
+	memory.UpdatedAt = &now
+}
+
+// delete removes a memory; the caller has checked that it exists
+func (s *MemoryServer) delete(id string) {
+	delete(s.memories, id)
+	for i, orderedID := range s.order {
+		if orderedID == id {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// list returns one page of an agent's memories
+func (s *MemoryServer) list(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	query, err := serverFilterQuery(values)
+	if err != nil {
+		writeServerError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	pageSize := memoryServerPageSize
+	if size := values.Get("page_size"); size != "" {
+		if pageSize, err = strconv.Atoi(size); err != nil || pageSize <= 0 {
+			writeServerError(w, http.StatusBadRequest, "invalid page_size")
+			return
+		}
+	}
+
+	matches := s.match(query)
+	if sortBy := SortField(values.Get("sort_by")); sortBy != "" {
+		sortMemories(matches, sortBy, SortDesc)
+	}
+	page, next, err := serverPage(matches, values.Get("cursor"), pageSize)
+	if err != nil {
+		writeServerError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	memories := make([]Memory, len(page))
+	for i, memory := range page {
+		memories[i] = *memory
+	}
+	writeServerJSON(w, http.StatusOK, ListMemoriesResult{Memories: memories, NextCursor: next})
+}
+
+// embeddings returns one page of the embeddings of an agent's memories
+func (s *MemoryServer) embeddings(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	agentID := values.Get("agent_id")
+	if agentID == "" {
+		writeServerError(w, http.StatusBadRequest, "agent_id is required")
+		return
+	}
+	encoding := EmbeddingEncoding(values.Get("encoding"))
+	if encoding != EmbeddingEncodingFloat && encoding != EmbeddingEncodingBase64 {
+		writeServerError(w, http.StatusBadRequest, "invalid encoding")
+		return
+	}
+	pageSize := memoryServerPageSize
+	if size := values.Get("page_size"); size != "" {
+		var err error
+		if pageSize, err = strconv.Atoi(size); err != nil || pageSize <= 0 {
+			writeServerError(w, http.StatusBadRequest, "invalid page_size")
+			return
+		}
+	}
+
+	var matches []*Memory
+	for _, memory := range s.match(SearchQuery{AgentID: agentID}) {
+		if len(memory.Embedding) > 0 {
+			matches = append(matches, memory)
+		}
+	}
+	page, next, err := serverPage(matches, values.Get("cursor"), pageSize)
+	if err != nil {
+		writeServerError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	type item struct {
+		ID        string      `json:"id"`
+		Embedding interface{} `json:"embedding"`
+	}
+	items := make([]item, len(page))
+	for i, memory := range page {
+		items[i] = item{ID: memory.ID, Embedding: memory.Embedding}
+		if encoding == EmbeddingEncodingBase64 {
+			items[i].Embedding = encodeEmbedding(memory.Embedding)
+		}
+	}
+	writeServerJSON(w, http.StatusOK, map[string]interface{}{"embeddings": items, "next_cursor": next})
+}
+
+// search answers a search query
+func (s *MemoryServer) search(w http.ResponseWriter, r *http.Request) {
+	var query SearchQuery
+	if !decodeServerBody(w, r, &query) {
+		return
+	}
+	text := ""
+	if query.TextQuery != nil {
+		text = strings.ToLower(*query.TextQuery)
+	}
+	// A hybrid query is refused too, rather than ranked by its text alone
+	if len(query.VectorQuery) > 0 {
+		writeServerError(w, http.StatusNotImplemented, "vector search is not implemented by MemoryServer")
+		return
+	}
+	if err := query.Validate(); err != nil {
+		writeServerError(w, http.StatusBadRequest, validationMessage(err))
+		return
+	}
+
+	var results []SearchResult
+	for _, memory := range s.match(query) {
+		result := SearchResult{Memory: *memory, Score: 1, MatchType: MatchTypeMetadata}
+		if !query.IncludeEmbeddings {
+			result.Memory.Embedding = nil
+		}
+		if text != "" {
+			content := strings.ToLower(memory.Content)
+			if !strings.Contains(content, text) {
+				continue
+			}
+			result.MatchType = MatchTypePartialText
+			result.Score = float64(len(text)) / float64(len(content))
+			if content == text {
+				result.MatchType = MatchTypeExactText
+			}
+		}
+		results = append(results, result)
+	}
+
+	switch query.SortBy {
+	case "", SortByRelevance:
+		sort.SliceStable(results, func(i, j int) bool {
+			if query.SortOrder == SortAsc {
+				return results[i].Score < results[j].Score
+			}
+			return results[i].Score > results[j].Score
+		})
+	default:
+		memories := make([]*Memory, len(results))
+		scores := make(map[string]SearchResult, len(results))
+		for i := range results {
+			memories[i] = &results[i].Memory
+			scores[results[i].Memory.ID] = results[i]
+		}
+		sortMemories(memories, query.SortBy, query.SortOrder)
+		sorted := make([]SearchResult, len(memories))
+		for i, memory := range memories {
+			sorted[i] = scores[memory.ID]
+		}
+		results = sorted
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = len(results) + 1
+	}
+	page, next, err := serverPage(results, query.Cursor, limit)
+	if err != nil {
+		writeServerError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	response := SearchResponse{Results: page, NextCursor: next}
+	if query.IncludeTotal {
+		total := len(results)
+		response.Total = &total
+	}
+	writeServerJSON(w, http.StatusOK, response)
+}
+
+// batchCreate creates several memories, or none if any is invalid
+func (s *MemoryServer) batchCreate(w http.ResponseWriter, r *http.Request) {
+	var params BatchCreateMemoryParams
+	if !decodeServerBody(w, r, &params) {
+		return
+	}
+	for i, memory := range params.Memories {
+		if err := memory.Validate(); err != nil {
+			writeServerError(w, http.StatusBadRequest, fmt.Sprintf("memories[%d]: %s", i, validationMessage(err)))
+			return
+		}
+	}
+	ids := make([]string, len(params.Memories))
+	for i, params := range params.Memories {
+		memory, _ := s.create(params)
+		ids[i] = memory.ID
+	}
+	writeServerJSON(w, http.StatusCreated, BatchCreateResponse{IDs: ids})
+}
+
+// batchGet returns the requested memories that exist
+func (s *MemoryServer) batchGet(w http.ResponseWriter, r *http.Request) {
+	var params BatchGetMemoryParams
+	if !decodeServerBody(w, r, &params) {
+		return
+	}
+	memories := []Memory{}
+	for _, id := range params.IDs {
+		if memory, ok := s.memories[id]; ok {
+			found := *memory.Clone()
+			if params.ExcludeEmbedding {
+				found.Embedding = nil
+			}
+			memories = append(memories, found)
+		}
+	}
+	writeServerJSON(w, http.StatusOK, BatchGetResponse{Memories: memories})
+}
+
+// batchDelete deletes the requested memories, reporting missing ones
+func (s *MemoryServer) batchDelete(w http.ResponseWriter, r *http.Request) {
+	var params struct {
+		IDs []string `json:"ids"`
+	}
+	if !decodeServerBody(w, r, &params) {
+		return
+	}
+	response := BatchDeleteResponse{Deleted: []string{}, Failed: map[string]BatchItemError{}}
+	for _, id := range params.IDs {
+		if _, ok := s.memories[id]; !ok {
+			response.Failed[id] = BatchItemError{Error: "memory not found", StatusCode: http.StatusNotFound}
+			continue
+		}
+		s.delete(id)
+		response.Deleted = append(response.Deleted, id)
+	}
+	writeServerJSON(w, http.StatusOK, response)
+}
+
+// tags adds or removes tags of a memory
+func (s *MemoryServer) tags(w http.ResponseWriter, r *http.Request, id string) {
+	memory, ok := s.memories[id]
+	if !ok {
+		writeServerError(w, http.StatusNotFound, fmt.Sprintf("memory %s not found", id))
+		return
+	}
+	var params TagsParams
+	if !decodeServerBody(w, r, &params) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		for _, tag := range params.Tags {
+			if !containsString(memory.Tags, tag) {
+				memory.Tags = append(memory.Tags, tag)
+			}
+		}
+	case http.MethodDelete:
+		kept := memory.Tags[:0]
+		for _, tag := range memory.Tags {
+			if !containsString(params.Tags, tag) {
+				kept = append(kept, tag)
+			}
+		}
+		memory.Tags = kept
+	default:
+		writeServerError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	writeServerJSON(w, http.StatusOK, memory)
+}
+
+// stats computes statistics over the memories matching the query filters
+func (s *MemoryServer) stats(w http.ResponseWriter, r *http.Request) {
+	query, err := serverFilterQuery(r.URL.Query())
+	if err != nil {
+		writeServerError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	stats := MemoryStats{
+		MemoriesByType:  map[string]int{},
+		MemoriesByAgent: map[string]int{},
+	}
+	var totalImportance float64
+	var oldest time.Time
+	mostAccessed := -1
+	for _, memory := range s.match(query) {
+		stats.TotalMemories++
+		stats.MemoriesByType[string(memory.MemoryType)]++
+		stats.MemoriesByAgent[memory.AgentID]++
+		stats.TotalAccessCount += memory.AccessCount
+		totalImportance += memory.Importance
+		if memory.CreatedAt != nil && (oldest.IsZero() || memory.CreatedAt.Before(oldest)) {
+			oldest = *memory.CreatedAt
+		}
+		if memory.AccessCount > mostAccessed {
+			mostAccessed = memory.AccessCount
+			stats.MostAccessedMemoryID = clonePtr(&memory.ID)
+		}
+	}
+	if stats.TotalMemories > 0 {
+		stats.AverageImportance = totalImportance / float64(stats.TotalMemories)
+		stats.OldestMemoryAgeDays = time.Since(oldest).Hours() / 24
+	}
+	writeServerJSON(w, http.StatusOK, stats)
+}
+
+// count counts the memories matching the query filters
+func (s *MemoryServer) count(w http.ResponseWriter, r *http.Request) {
+	query, err := serverFilterQuery(r.URL.Query())
+	if err != nil {
+		writeServerError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeServerJSON(w, http.StatusOK, CountResponse{Count: len(s.match(query))})
+}
+
+// health reports the server as healthy
+func (s *MemoryServer) health(w http.ResponseWriter, r *http.Request) {
+	writeServerJSON(w, http.StatusOK, HealthStatus{
+		Status:    healthyStatus,
+		Version:   "memory",
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Services:  map[string]string{"storage": healthyStatus},
+	})
+}
+
+// match returns the stored memories matching the filters of query, in
+// creation order
+func (s *MemoryServer) match(query SearchQuery) []*Memory {
+	var matches []*Memory
+	for _, id := range s.order {
+		if memory := s.memories[id]; matchesQuery(memory, query) {
+			matches = append(matches, memory)
+		}
+	}
+	return matches
+}
+
+// matchesQuery reports whether memory passes every filter of query; text
+// and vector queries are not considered
+func matchesQuery(memory *Memory, query SearchQuery) bool {
+	if query.AgentID != "" && memory.AgentID != query.AgentID {
+		return false
+	}
+	if len(query.AgentIDs) > 0 && !containsString(query.AgentIDs, memory.AgentID) {
+		return false
+	}
+	if query.MemoryType != nil && memory.MemoryType != *query.MemoryType {
+		return false
+	}
+	if query.UserID != nil && (memory.UserID == nil || *memory.UserID != *query.UserID) {
+		return false
+	}
+	if query.MinImportance != nil && memory.Importance < *query.MinImportance {
+		return false
+	}
+	if query.MaxAgeSeconds != nil && memory.CreatedAt != nil &&
+		time.Since(*memory.CreatedAt) > time.Duration(*query.MaxAgeSeconds)*time.Second {
+		return false
+	}
+	if !inTimeRange(memory.CreatedAt, query.CreatedAfter, query.CreatedBefore) ||
+		!inTimeRange(memory.UpdatedAt, query.UpdatedAfter, query.UpdatedBefore) {
+		return false
+	}
+	if len(query.Tags) > 0 {
+		matched := 0
+		for _, tag := range query.Tags {
+			if containsString(memory.Tags, tag) {
+				matched++
+			}
+		}
+		if matched == 0 || (query.TagMatchMode == TagMatchAll && matched < len(query.Tags)) {
+			return false
+		}
+	}
+	for _, filter := range query.filters() {
+		if !matchesFilter(memory.Metadata, filter) {
+			return false
+		}
+	}
+	return true
+}
+
+// inTimeRange reports whether t lies within the optional bounds
+func inTimeRange(t, after, before *time.Time) bool {
+	if t == nil {
+		return after == nil && before == nil
+	}
+	return (after == nil || !t.Before(*after)) && (before == nil || !t.After(*before))
+}
+
+// matchesFilter reports whether metadata satisfies a metadata filter
+func matchesFilter(metadata map[string]interface{}, filter MetadataFilter) bool {
+	value, ok := metadata[filter.Field]
+	switch filter.Operator {
+	case FilterOpExists:
+		exists, _ := filter.Value.(bool)
+		return ok == (exists || filter.Value == nil)
+	case FilterOpEq:
+		return ok && jsonEqual(value, filter.Value)
+	case FilterOpNe:
+		return !ok || !jsonEqual(value, filter.Value)
+	case FilterOpIn:
+		candidates, _ := normalizeJSON(filter.Value).([]interface{})
+		for _, candidate := range candidates {
+			if ok && jsonEqual(value, candidate) {
+				return true
+			}
+		}
+		return false
+	case FilterOpContains:
+		switch v := normalizeJSON(value).(type) {
+		case string:
+			s, isString := filter.Value.(string)
+			return isString && strings.Contains(v, s)
+		case []interface{}:
+			for _, item := range v {
+				if jsonEqual(item, filter.Value) {
+					return true
+				}
+			}
+		}
+		return false
+	case FilterOpGt, FilterOpGte, FilterOpLt, FilterOpLte:
+		cmp, comparable := compareJSON(value, filter.Value)
+		if !ok || !comparable {
+			return false
+		}
+		switch filter.Operator {
+		case FilterOpGt:
+			return cmp > 0
+		case FilterOpGte:
+			return cmp >= 0
+		case FilterOpLt:
+			return cmp < 0
+		default:
+			return cmp <= 0
+		}
+	}
+	return false
+}
+
+// normalizeJSON round-trips a value through JSON, so that values set in Go
+// compare equal to the same values decoded from a request
+func normalizeJSON(value interface{}) interface{} {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return value
+	}
+	return normalized
+}
+
+// jsonEqual reports whether two values are equal as JSON
+func jsonEqual(a, b interface{}) bool {
+	return reflect.DeepEqual(normalizeJSON(a), normalizeJSON(b))
+}
+
+// compareJSON orders two numbers or two strings, reporting false for other
+// combinations
+func compareJSON(a, b interface{}) (int, bool) {
+	switch x := normalizeJSON(a).(type) {
+	case float64:
+		y, ok := normalizeJSON(b).(float64)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		}
+		return 0, true
+	case string:
+		y, ok := normalizeJSON(b).(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(x, y), true
+	}
+	return 0, false
+}
+
+// sortMemories sorts memories by a field, keeping creation order for ties
+func sortMemories(memories []*Memory, field SortField, order SortOrder) {
+	key := func(memory *Memory) float64 {
+		switch field {
+		case SortByImportance:
+			return memory.Importance
+		case SortByAccessCount:
+			return float64(memory.AccessCount)
+		case SortByCreatedAt:
+			if memory.CreatedAt != nil {
+				return float64(memory.CreatedAt.UnixNano())
+			}
+		case SortByUpdatedAt:
+			if memory.UpdatedAt != nil {
+				return float64(memory.UpdatedAt.UnixNano())
+			}
+		}
+		return 0
+	}
+	sort.SliceStable(memories, func(i, j int) bool {
+		if order == SortAsc {
+			return key(memories[i]) < key(memories[j])
+		}
+		return key(memories[i]) > key(memories[j])
+	})
+}
+
+// serverPage returns the page of items starting at the offset encoded in
+// cursor, and the cursor of the following page
+func serverPage[T any](items []T, cursor string, size int) ([]T, string, error) {
+	offset := 0
+	if cursor != "" {
+		var err error
+		if offset, err = strconv.Atoi(cursor); err != nil || offset < 0 {
+			return nil, "", fmt.Errorf("invalid cursor")
+		}
+	}
+	if offset > len(items) {
+		offset = len(items)
+	}
+	end := offset + size
+	if end >= len(items) {
+		return append([]T{}, items[offset:]...), "", nil
+	}
+	return append([]T{}, items[offset:end]...), strconv.Itoa(end), nil
+}
+
+// serverFilterQuery decodes the filters encoded by countQueryParams, and
+// the filters of a list request, into a query
+func serverFilterQuery(values url.Values) (SearchQuery, error) {
+	query := SearchQuery{AgentID: values.Get("agent_id")}
+	if query.AgentID == "" {
+		return query, fmt.Errorf("agent_id is required")
+	}
+	if memoryType := values.Get("memory_type"); memoryType != "" {
+		t := MemoryType(memoryType)
+		query.MemoryType = &t
+	}
+	if userID := values.Get("user_id"); userID != "" {
+		query.UserID = &userID
+	}
+	if value := values.Get("min_importance"); value != "" {
+		minImportance, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return query, fmt.Errorf("invalid min_importance")
+		}
+		query.MinImportance = &minImportance
+	}
+	if value := values.Get("max_age_seconds"); value != "" {
+		maxAge, err := strconv.Atoi(value)
+		if err != nil {
+			return query, fmt.Errorf("invalid max_age_seconds")
+		}
+		query.MaxAgeSeconds = &maxAge
+	}
+	for name, bound := range map[string]**time.Time{
+		"created_after":  &query.CreatedAfter,
+		"created_before": &query.CreatedBefore,
+		"updated_after":  &query.UpdatedAfter,
+		"updated_before": &query.UpdatedBefore,
+	} {
+		if value := values.Get(name); value != "" {
+			t, err := time.Parse(time.RFC3339Nano, value)
+			if err != nil {
+				return query, fmt.Errorf("invalid %s", name)
+			}
+			*bound = &t
+		}
+	}
+	if value := values.Get("advanced_filters"); value != "" {
+		if err := json.Unmarshal([]byte(value), &query.AdvancedFilters); err != nil {
+			return query, fmt.Errorf("invalid advanced_filters")
+		}
+	}
+	if value := values.Get("tags"); value != "" {
+		if err := json.Unmarshal([]byte(value), &query.Tags); err != nil {
+			return query, fmt.Errorf("invalid tags")
+		}
+		query.TagMatchMode = TagMatchMode(values.Get("tag_match_mode"))
+	}
+	return query, nil
+}
+
+// decodeServerBody decodes a JSON request body, which may be gzipped,
+// answering 400 if it cannot be decoded
+func decodeServerBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	var body io.Reader = r.Body
+	if strings.EqualFold(r.Header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			writeServerError(w, http.StatusBadRequest, "invalid gzip body")
+			return false
+		}
+		defer gz.Close()
+		body = gz
+	}
+	if err := json.NewDecoder(body).Decode(v); err != nil {
+		writeServerError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return false
+	}
+	return true
+}
+
+// writeServerJSON writes v as a JSON response
+func writeServerJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeServerError writes an API error response
+func writeServerError(w http.ResponseWriter, status int, message string) {
+	writeServerJSON(w, status, APIResponse{Error: &message, Status: status})
+}
+
+// containsString reports whether values contains s
+func containsString(values []string, s string) bool {
+	for _, value := range values {
+		if value == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,444 @@
+package agentmem
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// addTestMemory adds a memory through client, failing the test on error
+func addTestMemory(t *testing.T, client *Client, params CreateMemoryParams) string {
+	t.Helper()
+	if params.AgentID == "" {
+		params.AgentID = "agent"
+	}
+	id, err := client.AddMemory(context.Background(), params)
+	if err != nil {
+		t.Fatalf("AddMemory(%q): %v", params.Content, err)
+	}
+	return id
+}
+
+// searchIDs returns the memory IDs of results, sorted
+func searchIDs(results []SearchResult) []string {
+	ids := make([]string, len(results))
+	for i, result := range results {
+		ids[i] = result.Memory.ID
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// sortedIDs returns a sorted copy of ids
+func sortedIDs(ids ...string) []string {
+	sorted := append([]string(nil), ids...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func TestMemoryServer(t *testing.T) {
+	episodic := MemoryTypeEpisodic
+	semantic := MemoryTypeSemantic
+	high := 0.9
+	low := 0.2
+
+	tests := []struct {
+		name string
+		run  func(t *testing.T, ctx context.Context, client *Client, server *MemoryServer)
+	}{
+		{
+			name: "create and get",
+			run: func(t *testing.T, ctx context.Context, client *Client, server *MemoryServer) {
+				id := addTestMemory(t, client, CreateMemoryParams{
+					Content:    "met Ann for coffee",
+					MemoryType: &episodic,
+					Importance: &high,
+					Metadata:   map[string]interface{}{"place": "cafe"},
+					Tags:       []string{"social"},
+				})
+
+				memory, err := client.GetMemory(ctx, id)
+				if err != nil {
+					t.Fatalf("GetMemory: %v", err)
+				}
+				if memory.ID != id || memory.AgentID != "agent" || memory.Content != "met Ann for coffee" ||
+					memory.MemoryType != MemoryTypeEpisodic || memory.Importance != high ||
+					memory.Metadata["place"] != "cafe" || !reflect.DeepEqual(memory.Tags, []string{"social"}) {
+					t.Errorf("GetMemory = %+v, want the created memory", memory)
+				}
+				if memory.CreatedAt == nil || memory.AccessCount != 1 {
+					t.Errorf("CreatedAt = %v, AccessCount = %d, want set and 1", memory.CreatedAt, memory.AccessCount)
+				}
+				if stored := server.Memories(); len(stored) != 1 || stored[0].ID != id {
+					t.Errorf("server.Memories() = %+v, want the one created memory", stored)
+				}
+			},
+		},
+		{
+			name: "create applies defaults",
+			run: func(t *testing.T, ctx context.Context, client *Client, server *MemoryServer) {
+				id := addTestMemory(t, client, CreateMemoryParams{Content: "plain"})
+				memory, err := client.GetMemory(ctx, id)
+				if err != nil {
+					t.Fatalf("GetMemory: %v", err)
+				}
+				if memory.MemoryType != MemoryTypeUntyped || memory.Importance != 0.5 {
+					t.Errorf("MemoryType = %q, Importance = %v, want untyped and 0.5", memory.MemoryType, memory.Importance)
+				}
+			},
+		},
+		{
+			name: "get missing",
+			run: func(t *testing.T, ctx context.Context, client *Client, server *MemoryServer) {
+				_, err := client.GetMemory(ctx, "missing")
+				var notFound *NotFoundError
+				if !errors.As(err, &notFound) {
+					t.Errorf("error = %T %v, want *NotFoundError", err, err)
+				}
+			},
+		},
+		{
+			name: "update",
+			run: func(t *testing.T, ctx context.Context, client *Client, server *MemoryServer) {
+				id := addTestMemory(t, client, CreateMemoryParams{
+					Content:  "draft",
+					Metadata: map[string]interface{}{"a": "1", "b": "2"},
+				})
+				content := "final"
+				updated, err := client.UpdateMemory(ctx, id, UpdateMemoryParams{
+					Content:           &content,
+					Importance:        &high,
+					Metadata:          map[string]interface{}{"b": nil, "c": "3"},
+					MetadataMergeMode: MetadataMerge,
+				})
+				if err != nil {
+					t.Fatalf("UpdateMemory: %v", err)
+				}
+				want := map[string]interface{}{"a": "1", "c": "3"}
+				if updated.Content != "final" || updated.Importance != high || !reflect.DeepEqual(updated.Metadata, want) {
+					t.Errorf("UpdateMemory = %+v, want content final, importance %v, metadata %v", updated, high, want)
+				}
+
+				memory, err := client.GetMemory(ctx, id)
+				if err != nil {
+					t.Fatalf("GetMemory: %v", err)
+				}
+				if memory.Content != "final" || !reflect.DeepEqual(memory.Metadata, want) {
+					t.Errorf("GetMemory after update = %+v", memory)
+				}
+			},
+		},
+		{
+			name: "delete",
+			run: func(t *testing.T, ctx context.Context, client *Client, server *MemoryServer) {
+				id := addTestMemory(t, client, CreateMemoryParams{Content: "temporary"})
+				if err := client.DeleteMemory(ctx, id); err != nil {
+					t.Fatalf("DeleteMemory: %v", err)
+				}
+				var notFound *NotFoundError
+				if _, err := client.GetMemory(ctx, id); !errors.As(err, &notFound) {
+					t.Errorf("GetMemory after delete: error = %v, want *NotFoundError", err)
+				}
+				if err := client.DeleteMemory(ctx, id); !errors.As(err, &notFound) {
+					t.Errorf("second DeleteMemory: error = %v, want *NotFoundError", err)
+				}
+				if stored := server.Memories(); len(stored) != 0 {
+					t.Errorf("server.Memories() = %+v, want none", stored)
+				}
+			},
+		},
+		{
+			name: "search by text",
+			run: func(t *testing.T, ctx context.Context, client *Client, server *MemoryServer) {
+				exact := addTestMemory(t, client, CreateMemoryParams{Content: "Coffee"})
+				partial := addTestMemory(t, client, CreateMemoryParams{Content: "likes coffee in the morning"})
+				addTestMemory(t, client, CreateMemoryParams{Content: "drinks tea"})
+				addTestMemory(t, client, CreateMemoryParams{AgentID: "other", Content: "coffee"})
+
+				text := "coffee"
+				results, err := client.SearchMemories(ctx, SearchQuery{AgentID: "agent", TextQuery: &text, Limit: 10})
+				if err != nil {
+					t.Fatalf("SearchMemories: %v", err)
+				}
+				if len(results) != 2 || results[0].Memory.ID != exact || results[1].Memory.ID != partial {
+					t.Fatalf("results = %+v, want the exact then the partial match", results)
+				}
+				if results[0].MatchType != MatchTypeExactText || results[1].MatchType != MatchTypePartialText {
+					t.Errorf("match types = %q, %q, want exact then partial", results[0].MatchType, results[1].MatchType)
+				}
+			},
+		},
+		{
+			name: "search by filters",
+			run: func(t *testing.T, ctx context.Context, client *Client, server *MemoryServer) {
+				match := addTestMemory(t, client, CreateMemoryParams{Content: "a", MemoryType: &episodic, Importance: &high, Tags: []string{"work"}})
+				addTestMemory(t, client, CreateMemoryParams{Content: "b", MemoryType: &episodic, Importance: &low, Tags: []string{"work"}})
+				addTestMemory(t, client, CreateMemoryParams{Content: "c", MemoryType: &semantic, Importance: &high, Tags: []string{"work"}})
+				addTestMemory(t, client, CreateMemoryParams{Content: "d", MemoryType: &episodic, Importance: &high, Tags: []string{"home"}})
+
+				results, err := client.SearchMemories(ctx, SearchQuery{
+					AgentID:       "agent",
+					MemoryType:    &episodic,
+					MinImportance: &high,
+					Tags:          []string{"work"},
+					Limit:         10,
+				})
+				if err != nil {
+					t.Fatalf("SearchMemories: %v", err)
+				}
+				if got := searchIDs(results); !reflect.DeepEqual(got, []string{match}) {
+					t.Errorf("results = %v, want only %s", got, match)
+				}
+			},
+		},
+		{
+			name: "search paging",
+			run: func(t *testing.T, ctx context.Context, client *Client, server *MemoryServer) {
+				var ids []string
+				for _, content := range []string{"one", "two", "three", "four", "five"} {
+					ids = append(ids, addTestMemory(t, client, CreateMemoryParams{Content: content, Tags: []string{"n"}}))
+				}
+				// Limit 0 fetches every page
+				results, err := client.SearchMemories(ctx, SearchQuery{AgentID: "agent", Tags: []string{"n"}})
+				if err != nil {
+					t.Fatalf("SearchMemories: %v", err)
+				}
+				if got := searchIDs(results); !reflect.DeepEqual(got, sortedIDs(ids...)) {
+					t.Errorf("results = %v, want all of %v", got, ids)
+				}
+			},
+		},
+		{
+			name: "batch",
+			run: func(t *testing.T, ctx context.Context, client *Client, server *MemoryServer) {
+				ids, err := client.BatchAddMemories(ctx, BatchCreateMemoryParams{Memories: []CreateMemoryParams{
+					{AgentID: "agent", Content: "first"},
+					{AgentID: "agent", Content: "second"},
+					{AgentID: "agent", Content: "third"},
+				}})
+				if err != nil {
+					t.Fatalf("BatchAddMemories: %v", err)
+				}
+				if len(ids) != 3 {
+					t.Fatalf("BatchAddMemories returned %d IDs, want 3", len(ids))
+				}
+
+				memories, err := client.GetMemories(ctx, []string{ids[2], "missing", ids[0]}, Projection{})
+				if err != nil {
+					t.Fatalf("GetMemories: %v", err)
+				}
+				if memories[0] == nil || memories[0].Content != "third" || memories[1] != nil ||
+					memories[2] == nil || memories[2].Content != "first" {
+					t.Errorf("GetMemories = %+v, want third, nil, first", memories)
+				}
+
+				result, err := client.BatchDeleteMemories(ctx, []string{ids[0], "missing"})
+				if err != nil {
+					t.Fatalf("BatchDeleteMemories: %v", err)
+				}
+				if !reflect.DeepEqual(result.Deleted, []string{ids[0]}) || len(result.Failed) != 1 {
+					t.Errorf("BatchDeleteMemories = %+v, want %s deleted and missing failed", result, ids[0])
+				}
+				var notFound *NotFoundError
+				if !errors.As(result.Failed["missing"], &notFound) {
+					t.Errorf("failure for missing = %v, want *NotFoundError", result.Failed["missing"])
+				}
+				if stored := server.Memories(); len(stored) != 2 {
+					t.Errorf("server holds %d memories, want 2", len(stored))
+				}
+			},
+		},
+		{
+			name: "batch with an invalid memory creates none",
+			run: func(t *testing.T, ctx context.Context, client *Client, server *MemoryServer) {
+				_, err := client.BatchAddMemories(ctx, BatchCreateMemoryParams{Memories: []CreateMemoryParams{
+					{AgentID: "agent", Content: "valid"},
+					{AgentID: "agent"},
+				}})
+				var validationErr *ValidationError
+				if !errors.As(err, &validationErr) {
+					t.Errorf("error = %T %v, want *ValidationError", err, err)
+				}
+				if stored := server.Memories(); len(stored) != 0 {
+					t.Errorf("server holds %d memories, want none", len(stored))
+				}
+			},
+		},
+		{
+			name: "stats",
+			run: func(t *testing.T, ctx context.Context, client *Client, server *MemoryServer) {
+				addTestMemory(t, client, CreateMemoryParams{Content: "a", MemoryType: &episodic, Importance: &high})
+				addTestMemory(t, client, CreateMemoryParams{Content: "b", MemoryType: &episodic, Importance: &low})
+				addTestMemory(t, client, CreateMemoryParams{Content: "c", MemoryType: &semantic, Importance: &high})
+				addTestMemory(t, client, CreateMemoryParams{AgentID: "other", Content: "d"})
+
+				stats, err := client.GetMemoryStats(ctx, "agent")
+				if err != nil {
+					t.Fatalf("GetMemoryStats: %v", err)
+				}
+				wantByType := map[string]int{"episodic": 2, "semantic": 1}
+				if stats.TotalMemories != 3 || !reflect.DeepEqual(stats.MemoriesByType, wantByType) {
+					t.Errorf("stats = %+v, want 3 memories by type %v", stats, wantByType)
+				}
+				if want := (high + low + high) / 3; stats.AverageImportance < want-1e-9 || stats.AverageImportance > want+1e-9 {
+					t.Errorf("AverageImportance = %v, want %v", stats.AverageImportance, want)
+				}
+
+				filtered, err := client.GetMemoryStatsFiltered(ctx, StatsQuery{AgentID: "agent", MemoryType: &episodic})
+				if err != nil {
+					t.Fatalf("GetMemoryStatsFiltered: %v", err)
+				}
+				if filtered.TotalMemories != 2 {
+					t.Errorf("filtered TotalMemories = %d, want 2", filtered.TotalMemories)
+				}
+			},
+		},
+		{
+			name: "count",
+			run: func(t *testing.T, ctx context.Context, client *Client, server *MemoryServer) {
+				addTestMemory(t, client, CreateMemoryParams{Content: "a", MemoryType: &episodic, Importance: &high})
+				addTestMemory(t, client, CreateMemoryParams{Content: "b", MemoryType: &episodic, Importance: &low})
+				addTestMemory(t, client, CreateMemoryParams{Content: "c", MemoryType: &semantic, Importance: &high})
+
+				counts := []struct {
+					query SearchQuery
+					want  int
+				}{
+					{SearchQuery{AgentID: "agent"}, 3},
+					{SearchQuery{AgentID: "agent", MemoryType: &episodic}, 2},
+					{SearchQuery{AgentID: "agent", MemoryType: &episodic, MinImportance: &high}, 1},
+					{SearchQuery{AgentID: "other"}, 0},
+				}
+				for _, count := range counts {
+					got, err := client.CountMemories(ctx, count.query)
+					if err != nil {
+						t.Fatalf("CountMemories: %v", err)
+					}
+					if got != count.want {
+						t.Errorf("CountMemories(%+v) = %d, want %d", count.query, got, count.want)
+					}
+				}
+			},
+		},
+		{
+			name: "tags",
+			run: func(t *testing.T, ctx context.Context, client *Client, server *MemoryServer) {
+				id := addTestMemory(t, client, CreateMemoryParams{Content: "tagged", Tags: []string{"a"}})
+				if err := client.AddTags(ctx, id, []string{"a", "b", "c"}); err != nil {
+					t.Fatalf("AddTags: %v", err)
+				}
+				if err := client.RemoveTags(ctx, id, []string{"a"}); err != nil {
+					t.Fatalf("RemoveTags: %v", err)
+				}
+				memory, err := client.GetMemory(ctx, id)
+				if err != nil {
+					t.Fatalf("GetMemory: %v", err)
+				}
+				if !reflect.DeepEqual(memory.Tags, []string{"b", "c"}) {
+					t.Errorf("Tags = %v, want [b c]", memory.Tags)
+				}
+
+				var notFound *NotFoundError
+				if err := client.AddTags(ctx, "missing", []string{"a"}); !errors.As(err, &notFound) {
+					t.Errorf("AddTags on a missing memory: error = %v, want *NotFoundError", err)
+				}
+			},
+		},
+		{
+			name: "list",
+			run: func(t *testing.T, ctx context.Context, client *Client, server *MemoryServer) {
+				var ids []string
+				for _, content := range []string{"one", "two", "three"} {
+					ids = append(ids, addTestMemory(t, client, CreateMemoryParams{Content: content}))
+				}
+
+				first, err := client.ListMemories(ctx, ListMemoriesParams{AgentID: "agent", PageSize: 2})
+				if err != nil {
+					t.Fatalf("ListMemories: %v", err)
+				}
+				if len(first.Memories) != 2 || first.NextCursor == "" {
+					t.Fatalf("first page = %+v, want 2 memories and a cursor", first)
+				}
+				second, err := client.ListMemories(ctx, ListMemoriesParams{AgentID: "agent", PageSize: 2, Cursor: first.NextCursor})
+				if err != nil {
+					t.Fatalf("ListMemories: %v", err)
+				}
+				if len(second.Memories) != 1 || second.NextCursor != "" || second.Memories[0].ID != ids[2] {
+					t.Errorf("second page = %+v, want only %s and no cursor", second, ids[2])
+				}
+			},
+		},
+		{
+			name: "health",
+			run: func(t *testing.T, ctx context.Context, client *Client, server *MemoryServer) {
+				health, err := client.HealthCheck(ctx)
+				if err != nil {
+					t.Fatalf("HealthCheck: %v", err)
+				}
+				if health.Status != healthyStatus {
+					t.Errorf("Status = %q, want %q", health.Status, healthyStatus)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewMemoryServer()
+			tt.run(t, context.Background(), NewTestClient(server), server)
+		})
+	}
+}
+
+func TestMemoryServerUnsupportedEndpoints(t *testing.T) {
+	server := NewMemoryServer()
+	client := NewTestClient(server)
+	ctx := context.Background()
+	id := addTestMemory(t, client, CreateMemoryParams{Content: "source", Tags: []string{"t"}})
+	other := addTestMemory(t, client, CreateMemoryParams{Content: "target"})
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"decay", func() error {
+			_, err := client.ApplyDecay(ctx, DecayParams{AgentID: "agent", HalfLife: time.Hour})
+			return err
+		}},
+		{"delete by filter", func() error {
+			_, err := client.DeleteMemoriesByFilter(ctx, SearchQuery{AgentID: "agent", Tags: []string{"t"}})
+			return err
+		}},
+		{"links", func() error {
+			return client.LinkMemories(ctx, id, other, "related_to")
+		}},
+		{"history", func() error {
+			_, err := client.GetMemoryHistory(ctx, id)
+			return err
+		}},
+		{"vector search", func() error {
+			_, err := client.SearchByVector(ctx, []float64{0.1}, SearchQuery{AgentID: "agent", Limit: 5})
+			return err
+		}},
+		{"hybrid search", func() error {
+			_, err := client.HybridSearch(ctx, "source", []float64{0.1}, SearchQuery{AgentID: "agent", Limit: 5})
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			apiErr, ok := asAgentMemError(err)
+			if !ok || apiErr.StatusCode != http.StatusNotImplemented {
+				t.Errorf("error = %T %v, want a %d API error", err, err, http.StatusNotImplemented)
+			}
+		})
+	}
+
+	if stored := server.Memories(); len(stored) != 2 {
+		t.Errorf("server holds %d memories after unsupported calls, want 2", len(stored))
+	}
+}
@@ -0,0 +1,118 @@
+package agentmem
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// clientMetrics exports client-observed request metrics to Prometheus.
+// A nil *clientMetrics records nothing, so callers need not check whether
+// metrics are enabled.
+type clientMetrics struct {
+	requests    *prometheus.CounterVec
+	duration    *prometheus.HistogramVec
+	retries     *prometheus.CounterVec
+	cacheHits   prometheus.Counter
+	cacheMisses prometheus.Counter
+}
+
+// newClientMetrics registers the client collectors with reg. Collectors
+// already registered by another client are shared, so several clients can
+// report to one registry. A nil reg disables metrics.
+func newClientMetrics(reg prometheus.Registerer) (*clientMetrics, error) {
+	if reg == nil {
+		return nil, nil
+	}
+
+	var err error
+	metrics := &clientMetrics{}
+	if metrics.requests, err = registerCollector(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "agentmem_client_requests_total",
+		Help: "API requests sent by the AgentMem client, by operation and HTTP status.",
+	}, []string{"operation", "status"})); err != nil {
+		return nil, err
+	}
+	if metrics.duration, err = registerCollector(reg, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "agentmem_client_request_duration_seconds",
+		Help:    "Duration of AgentMem API requests, including retries.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"operation"})); err != nil {
+		return nil, err
+	}
+	if metrics.retries, err = registerCollector(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "agentmem_client_retries_total",
+		Help: "Retried AgentMem API request attempts, by operation.",
+	}, []string{"operation"})); err != nil {
+		return nil, err
+	}
+	if metrics.cacheHits, err = registerCollector(reg, prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "agentmem_client_cache_hits_total",
+		Help: "AgentMem client response cache hits.",
+	})); err != nil {
+		return nil, err
+	}
+	if metrics.cacheMisses, err = registerCollector(reg, prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "agentmem_client_cache_misses_total",
+		Help: "AgentMem client response cache misses.",
+	})); err != nil {
+		return nil, err
+	}
+	return metrics, nil
+}
+
+// registerCollector registers a collector, returning the existing one when
+// an identical collector is already registered
+func registerCollector[T prometheus.Collector](reg prometheus.Registerer, collector T) (T, error) {
+	if err := reg.Register(collector); err != nil {
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+		if errors.As(err, &alreadyRegistered) {
+			if existing, ok := alreadyRegistered.ExistingCollector.(T); ok {
+				return existing, nil
+			}
+		}
+		return collector, err
+	}
+	return collector, nil
+}
+
+// observeRequest records a completed API call and its duration
+func (m *clientMetrics) observeRequest(ctx context.Context, method, endpoint string, resp *resty.Response, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	operation := operationName(method, endpointRoute(endpoint))
+	status := "error"
+	if resp != nil && resp.RawResponse != nil {
+		status = strconv.Itoa(resp.StatusCode())
+	} else if ctx.Err() != nil {
+		status = "cancelled"
+	}
+	m.requests.WithLabelValues(operation, status).Inc()
+	m.duration.WithLabelValues(operation).Observe(duration.Seconds())
+}
+
+// recordRetry records a retried attempt of an API call, given the route of
+// its endpoint as returned by requestRoute, without a query string
+func (m *clientMetrics) recordRetry(method, route string) {
+	if m == nil {
+		return
+	}
+	m.retries.WithLabelValues(operationName(method, route)).Inc()
+}
+
+// recordCacheLookup records a response cache hit or miss
+func (m *clientMetrics) recordCacheLookup(hit bool) {
+	if m == nil {
+		return
+	}
+	if hit {
+		m.cacheHits.Inc()
+	} else {
+		m.cacheMisses.Inc()
+	}
+}
@@ -0,0 +1,49 @@
+package agentmem
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetricsRetriesLabeledByOperation(t *testing.T) {
+	var mu sync.Mutex
+	attempts := make(map[string]int)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts[r.URL.String()]++
+		first := attempts[r.URL.String()] == 1
+		mu.Unlock()
+		if first {
+			writeServerError(w, http.StatusServiceUnavailable, "unavailable")
+			return
+		}
+		writeServerJSON(w, http.StatusOK, MemoryStats{})
+	})
+	registry := prometheus.NewRegistry()
+	client := newHandlerClient(t, handler, func(config *Config) {
+		config.MaxRetries = 1
+		config.PrometheusRegisterer = registry
+	})
+
+	ctx := context.Background()
+	for _, agentID := range []string{"agent_1", "agent_2"} {
+		if _, err := client.GetMemoryStats(ctx, agentID); err != nil {
+			t.Fatalf("GetMemoryStats(%s): %v", agentID, err)
+		}
+	}
+
+	if got := testutil.ToFloat64(client.metrics.retries.WithLabelValues("GetMemoryStats")); got != 2 {
+		t.Errorf("GetMemoryStats retries = %v, want 2", got)
+	}
+	if got := testutil.CollectAndCount(client.metrics.retries); got != 1 {
+		t.Errorf("retry counter has %d series, want 1", got)
+	}
+	if got := testutil.ToFloat64(client.metrics.requests.WithLabelValues("GetMemoryStats", "200")); got != 2 {
+		t.Errorf("GetMemoryStats requests = %v, want 2", got)
+	}
+}
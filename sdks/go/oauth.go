@@ -0,0 +1,135 @@
+package agentmem
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenRefreshMargin is how long before its expiry an OAuth2 token is
+// refreshed, so requests in flight do not carry an expired token
+const tokenRefreshMargin = 30 * time.Second
+
+// OAuth2Config configures the OAuth2 client-credentials grant used to
+// authenticate instead of a static API key
+type OAuth2Config struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
+// oauth2Token is the token endpoint response
+type oauth2Token struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// tokenSource fetches OAuth2 access tokens and caches them until shortly
+// before they expire
+type tokenSource struct {
+	config     OAuth2Config
+	httpClient *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiry      time.Time
+}
+
+// newTokenSource creates a token source fetching tokens with httpClient
+func newTokenSource(config OAuth2Config, httpClient *http.Client) *tokenSource {
+	return &tokenSource{config: config, httpClient: httpClient}
+}
+
+// token returns a valid access token, fetching a new one when none is
+// cached or the cached one is about to expire
+func (s *tokenSource) token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.accessToken != "" && (s.expiry.IsZero() || time.Until(s.expiry) > tokenRefreshMargin) {
+		return s.accessToken, nil
+	}
+
+	token, err := s.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.accessToken = token.AccessToken
+	s.expiry = time.Time{}
+	if token.ExpiresIn > 0 {
+		s.expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+	return s.accessToken, nil
+}
+
+// invalidate drops the cached token, e.g. after the API rejected it
+func (s *tokenSource) invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accessToken = ""
+}
+
+// fetch requests a new token from the token endpoint
+func (s *tokenSource) fetch(ctx context.Context) (*oauth2Token, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(s.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(s.config.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(s.config.ClientID), url.QueryEscape(s.config.ClientSecret))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, requestError(ctx, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, requestError(ctx, err)
+	}
+
+	if resp.StatusCode >= 400 {
+		message := fmt.Sprintf("OAuth2 token request failed: %s", errorMessage(resp.StatusCode, resp.Status, body))
+		if resp.StatusCode >= 500 {
+			return nil, NewServerError(message)
+		}
+		return nil, NewAuthenticationError(message)
+	}
+
+	var token oauth2Token
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, NewDecodeError(resp.StatusCode, err)
+	}
+	if token.AccessToken == "" {
+		return nil, NewAuthenticationError("OAuth2 token response has no access token")
+	}
+	return &token, nil
+}
+
+// authorize sets the Authorization header of a request to a current OAuth2
+// bearer token. Without OAuth2 the API key header is left as is.
+func (c *Client) authorize(req *http.Request) error {
+	if c.tokens == nil {
+		return nil
+	}
+	token, err := c.tokens.token(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
@@ -0,0 +1,270 @@
+package agentmem
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// localIDPrefix marks the provisional IDs of memories queued offline
+const localIDPrefix = "local-"
+
+// QueuedMemory is a memory whose creation is waiting in the offline queue
+type QueuedMemory struct {
+	// LocalID is the provisional ID returned by AddMemory
+	LocalID  string             `json:"local_id"`
+	Params   CreateMemoryParams `json:"params"`
+	QueuedAt time.Time          `json:"queued_at"`
+	// IdempotencyKey is the key of the original AddMemory call, reused when
+	// the memory is flushed in case that call reached the server
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// OfflineStore persists the offline queue. Implementations must be safe
+// for concurrent use and return entries in the order they were appended.
+type OfflineStore interface {
+	Append(entry QueuedMemory) error
+	List() ([]QueuedMemory, error)
+	Remove(localID string) error
+}
+
+// NewMemoryOfflineStore creates an offline store that keeps the queue in
+// memory only, so it does not survive a restart
+func NewMemoryOfflineStore() OfflineStore {
+	return &memoryOfflineStore{}
+}
+
+// memoryOfflineStore is an in-memory OfflineStore
+type memoryOfflineStore struct {
+	mu      sync.Mutex
+	entries []QueuedMemory
+}
+
+func (s *memoryOfflineStore) Append(entry QueuedMemory) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func (s *memoryOfflineStore) List() ([]QueuedMemory, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]QueuedMemory(nil), s.entries...), nil
+}
+
+func (s *memoryOfflineStore) Remove(localID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = removeQueued(s.entries, localID)
+	return nil
+}
+
+// NewFileOfflineStore creates an offline store that persists the queue as
+// JSON in the file at path, loading any entries already stored there.
+// Every change rewrites the file atomically, which suits queues of the
+// size an intermittently connected agent builds up.
+func NewFileOfflineStore(path string) (OfflineStore, error) {
+	store := &fileOfflineStore{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read offline queue: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &store.entries); err != nil {
+			return nil, fmt.Errorf("failed to decode offline queue: %w", err)
+		}
+	}
+	return store, nil
+}
+
+// fileOfflineStore is an OfflineStore backed by a JSON file
+type fileOfflineStore struct {
+	path    string
+	mu      sync.Mutex
+	entries []QueuedMemory
+}
+
+func (s *fileOfflineStore) Append(entry QueuedMemory) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.save(append(s.entries, entry))
+}
+
+func (s *fileOfflineStore) List() ([]QueuedMemory, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]QueuedMemory(nil), s.entries...), nil
+}
+
+func (s *fileOfflineStore) Remove(localID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := removeQueued(append([]QueuedMemory(nil), s.entries...), localID)
+	return s.save(entries)
+}
+
+// save writes entries to a temporary file and renames it over the queue
+// file, so a crash never leaves a partially written queue. The caller must
+// hold mu.
+func (s *fileOfflineStore) save(entries []QueuedMemory) error {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode offline queue: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write offline queue: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write offline queue: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write offline queue: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write offline queue: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write offline queue: %w", err)
+	}
+	s.entries = entries
+	return nil
+}
+
+// removeQueued removes the entry with localID from entries
+func removeQueued(entries []QueuedMemory, localID string) []QueuedMemory {
+	for i, entry := range entries {
+		if entry.LocalID == localID {
+			return append(entries[:i], entries[i+1:]...)
+		}
+	}
+	return entries
+}
+
+// IsLocalID reports whether id is a provisional ID of a memory queued
+// offline rather than a server-assigned ID
+func IsLocalID(id string) bool {
+	return strings.HasPrefix(id, localIDPrefix)
+}
+
+// enqueueOffline queues prepared memory params after a network failure and
+// returns the memory's provisional ID
+func (c *Client) enqueueOffline(params CreateMemoryParams) (string, error) {
+	entry := QueuedMemory{
+		LocalID:        localIDPrefix + newUUID(),
+		Params:         params,
+		QueuedAt:       time.Now(),
+		IdempotencyKey: params.IdempotencyKey,
+	}
+	if err := c.config.OfflineStore.Append(entry); err != nil {
+		return "", err
+	}
+	c.logger.Warnf("Queued memory %s offline after a network failure", entry.LocalID)
+	return entry.LocalID, nil
+}
+
+// PendingCount returns the number of memories waiting in the offline queue
+func (c *Client) PendingCount() int {
+	if c.config.OfflineStore == nil {
+		return 0
+	}
+	entries, err := c.config.OfflineStore.List()
+	if err != nil {
+		return 0
+	}
+	return len(entries)
+}
+
+// FlushOfflineQueue replays queued memories to the server in the order they
+// were queued and returns how many were created. It stops at the first
+// failure that may be temporary, such as a network or server error, leaving
+// that memory and the ones after it queued. A memory the server rejects is
+// dropped from the queue and reported to OnOfflineFlush with the error.
+func (c *Client) FlushOfflineQueue(ctx context.Context) (int, error) {
+	if c.config.OfflineStore == nil {
+		return 0, nil
+	}
+	c.offlineMu.Lock()
+	defer c.offlineMu.Unlock()
+
+	entries, err := c.config.OfflineStore.List()
+	if err != nil {
+		return 0, err
+	}
+
+	flushed := 0
+	for _, entry := range entries {
+		var response CreateMemoryResponse
+		err := c.makeRequestWithOptions(ctx, "POST", "/memories", entry.Params, &response, idempotencyOptions(entry.IdempotencyKey))
+		if err != nil && isTemporaryError(err) {
+			return flushed, err
+		}
+		if removeErr := c.config.OfflineStore.Remove(entry.LocalID); removeErr != nil {
+			return flushed, removeErr
+		}
+		if err == nil {
+			flushed++
+		}
+		if c.config.OnOfflineFlush != nil {
+			c.config.OnOfflineFlush(entry.LocalID, response.ID, err)
+		}
+	}
+	return flushed, nil
+}
+
+// RunOfflineFlusher flushes the offline queue every interval while the
+// backend reports itself healthy, until ctx is done or the client is
+// closed. It blocks, so run it in its own goroutine.
+func (c *Client) RunOfflineFlusher(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return NewValidationError("interval must be positive")
+	}
+	ctx, cancel, err := c.backgroundContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.background.Done()
+	defer cancel()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if c.PendingCount() == 0 {
+			continue
+		}
+		if health, err := c.healthCheck(ctx, false); err != nil || health.Status != healthyStatus {
+			continue
+		}
+		if _, err := c.FlushOfflineQueue(ctx); err != nil {
+			c.logger.Warnf("Offline queue flush stopped: %v", err)
+		}
+	}
+}
+
+// isTemporaryError reports whether a failed request may succeed if
+// repeated later
+func isTemporaryError(err error) bool {
+	var networkErr *NetworkError
+	var serverErr *ServerError
+	var rateLimitErr *RateLimitError
+	var cancelledErr *CancelledError
+	var circuitErr *CircuitOpenError
+	return errors.As(err, &networkErr) || errors.As(err, &serverErr) ||
+		errors.As(err, &rateLimitErr) || errors.As(err, &cancelledErr) ||
+		errors.As(err, &circuitErr)
+}
@@ -0,0 +1,145 @@
+package agentmem
+
+import "sort"
+
+// Clone returns a deep copy of the query. Pointer fields, slices, and the
+// metadata map are copied, so the clone can be modified or used from another
+// goroutine without affecting the original.
+func (q SearchQuery) Clone() SearchQuery {
+	clone := q
+	clone.TextQuery = clonePtr(q.TextQuery)
+	clone.MemoryType = clonePtr(q.MemoryType)
+	clone.UserID = clonePtr(q.UserID)
+	clone.MinImportance = clonePtr(q.MinImportance)
+	clone.MaxAgeSeconds = clonePtr(q.MaxAgeSeconds)
+	clone.HybridWeight = clonePtr(q.HybridWeight)
+	clone.CreatedAfter = clonePtr(q.CreatedAfter)
+	clone.CreatedBefore = clonePtr(q.CreatedBefore)
+	clone.UpdatedAfter = clonePtr(q.UpdatedAfter)
+	clone.UpdatedBefore = clonePtr(q.UpdatedBefore)
+	clone.SnapshotID = clonePtr(q.SnapshotID)
+
+	if q.VectorQuery != nil {
+		clone.VectorQuery = append([]float64(nil), q.VectorQuery...)
+	}
+	if q.AgentIDs != nil {
+		clone.AgentIDs = append([]string(nil), q.AgentIDs...)
+	}
+	if q.Tags != nil {
+		clone.Tags = append([]string(nil), q.Tags...)
+	}
+	clone.MetadataFilters = cloneMetadata(q.MetadataFilters)
+	if q.AdvancedFilters != nil {
+		clone.AdvancedFilters = make([]MetadataFilter, len(q.AdvancedFilters))
+		for i, filter := range q.AdvancedFilters {
+			filter.Value = cloneValue(filter.Value)
+			clone.AdvancedFilters[i] = filter
+		}
+	}
+	return clone
+}
+
+// Clone returns a deep copy of the memory. Pointer fields, tags, the
+// embedding, relations, and the metadata map are copied, so the clone can be modified
+// without affecting the original.
+func (m *Memory) Clone() *Memory {
+	if m == nil {
+		return nil
+	}
+	clone := *m
+	clone.UserID = clonePtr(m.UserID)
+	clone.SessionID = clonePtr(m.SessionID)
+	clone.CreatedAt = clonePtr(m.CreatedAt)
+	clone.UpdatedAt = clonePtr(m.UpdatedAt)
+	clone.LastAccessed = clonePtr(m.LastAccessed)
+	clone.Metadata = cloneMetadata(m.Metadata)
+	if m.Tags != nil {
+		clone.Tags = append([]string(nil), m.Tags...)
+	}
+	if m.Embedding != nil {
+		clone.Embedding = append([]float64(nil), m.Embedding...)
+	}
+	if m.Relations != nil {
+		clone.Relations = append([]MemoryRelation(nil), m.Relations...)
+	}
+	return &clone
+}
+
+// wireQuery returns the query as sent to the server, with the MetadataFilters
+// map translated to equality filters ahead of AdvancedFilters
+func (q SearchQuery) wireQuery() SearchQuery {
+	q.AdvancedFilters = q.filters()
+	q.MetadataFilters = nil
+	return q
+}
+
+// filters returns all metadata filters of the query as structured filters.
+// MetadataFilters entries become Eq filters, ordered by key so that equal
+// queries serialize (and cache) identically.
+func (q SearchQuery) filters() []MetadataFilter {
+	if len(q.MetadataFilters) == 0 {
+		return q.AdvancedFilters
+	}
+	keys := make([]string, 0, len(q.MetadataFilters))
+	for key := range q.MetadataFilters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	filters := make([]MetadataFilter, 0, len(keys)+len(q.AdvancedFilters))
+	for _, key := range keys {
+		filters = append(filters, MetadataFilter{Field: key, Operator: FilterOpEq, Value: q.MetadataFilters[key]})
+	}
+	return append(filters, q.AdvancedFilters...)
+}
+
+// hasFilters reports whether the query restricts matches by anything other
+// than the agent, text, or vector
+func (q SearchQuery) hasFilters() bool {
+	return q.MemoryType != nil || q.UserID != nil || q.MinImportance != nil ||
+		q.MaxAgeSeconds != nil || q.CreatedAfter != nil || q.CreatedBefore != nil ||
+		q.UpdatedAfter != nil || q.UpdatedBefore != nil ||
+		len(q.MetadataFilters) > 0 || len(q.AdvancedFilters) > 0 || len(q.Tags) > 0
+}
+
+// clonePtr returns a pointer to a copy of *p, or nil when p is nil
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+// cloneMetadata deep-copies a metadata map
+func cloneMetadata(metadata map[string]interface{}) map[string]interface{} {
+	if metadata == nil {
+		return nil
+	}
+	clone := make(map[string]interface{}, len(metadata))
+	for key, value := range metadata {
+		clone[key] = cloneValue(value)
+	}
+	return clone
+}
+
+// cloneValue deep-copies the JSON-like containers (maps and slices) that
+// can appear in metadata values; other values are returned as is
+func cloneValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return cloneMetadata(v)
+	case []interface{}:
+		clone := make([]interface{}, len(v))
+		for i, item := range v {
+			clone[i] = cloneValue(item)
+		}
+		return clone
+	case []string:
+		return append([]string(nil), v...)
+	case []float64:
+		return append([]float64(nil), v...)
+	default:
+		return value
+	}
+}
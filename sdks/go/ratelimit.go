@@ -0,0 +1,34 @@
+package agentmem
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/time/rate"
+)
+
+// newRateLimiter returns the client-side rate limiter, or nil when rate
+// limiting is disabled
+func newRateLimiter(config *Config) *rate.Limiter {
+	if config.RateLimit <= 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(config.RateLimit), config.RateLimitBurst)
+}
+
+// waitForRateLimit blocks until the rate limiter allows another request or
+// ctx is done
+func (c *Client) waitForRateLimit(ctx context.Context) error {
+	if c.limiter == nil {
+		return nil
+	}
+	if err := c.limiter.Wait(ctx); err != nil {
+		if ctx.Err() != nil {
+			return NewCancelledError(ctx.Err())
+		}
+		// The limiter fails early when ctx's deadline would pass before a
+		// token becomes available
+		return NewCancelledError(fmt.Errorf("%w: %v", context.DeadlineExceeded, err))
+	}
+	return nil
+}
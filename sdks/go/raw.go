@@ -0,0 +1,37 @@
+package agentmem
+
+import (
+	"context"
+)
+
+// DoRaw sends a request to an API endpoint, relative to the API base URL
+// (e.g. "/memories/{id}"), and returns the raw response. It is an escape
+// hatch for endpoints the SDK does not model and for response headers the
+// typed methods discard. For GET requests body may be a
+// map[string]interface{} of query parameters; otherwise it is sent as JSON.
+//
+// Requests go through the same retries, rate limiting, circuit breaker,
+// hooks, and error handling as typed methods, but are never cached. API
+// errors are returned as the usual typed errors, together with the raw
+// response so its headers and body remain available.
+func (c *Client) DoRaw(ctx context.Context, method, endpoint string, body interface{}) (raw *RawResponse, err error) {
+	ctx, span := c.startSpan(ctx, method, endpoint, body)
+	defer func() { endSpan(span, err) }()
+
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, NewCancelledError(ctxErr)
+	}
+	if err := c.checkHealthGate(endpoint, RequestOptions{}); err != nil {
+		return nil, err
+	}
+
+	resp, _, err := c.send(ctx, span, method, endpoint, body, RequestOptions{})
+	if resp != nil && resp.RawResponse != nil {
+		raw = &RawResponse{
+			StatusCode: resp.StatusCode(),
+			Headers:    resp.Header(),
+			Body:       resp.Body(),
+		}
+	}
+	return raw, err
+}
@@ -0,0 +1,61 @@
+package agentmem
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// maskSecret hides all but a short prefix of a secret, and all of it when
+// the secret is too short for a prefix to be safe to show
+func maskSecret(secret string) string {
+	if len(secret) < 12 {
+		return "***"
+	}
+	return secret[:4] + "***"
+}
+
+// redactRequestLog masks credentials in resty's debug log of a request
+func (c *Client) redactRequestLog(rl *resty.RequestLog) error {
+	c.redactHeaders(rl.Header)
+	rl.Body = c.redactAPIKey(rl.Body)
+	return nil
+}
+
+// redactResponseLog masks credentials in resty's debug log of a response
+func (c *Client) redactResponseLog(rl *resty.ResponseLog) error {
+	c.redactHeaders(rl.Header)
+	rl.Body = c.redactAPIKey(rl.Body)
+	return nil
+}
+
+// redactHeaders masks the Authorization header and any other header value
+// containing the API key. resty's log headers share their value slices with
+// the request being sent, so masked values are stored in new slices.
+func (c *Client) redactHeaders(header http.Header) {
+	for name, values := range header {
+		redacted := make([]string, len(values))
+		for i, value := range values {
+			if http.CanonicalHeaderKey(name) == "Authorization" {
+				scheme, credentials, found := strings.Cut(value, " ")
+				if found {
+					redacted[i] = scheme + " " + maskSecret(credentials)
+				} else {
+					redacted[i] = maskSecret(value)
+				}
+				continue
+			}
+			redacted[i] = c.redactAPIKey(value)
+		}
+		header[name] = redacted
+	}
+}
+
+// redactAPIKey masks every occurrence of the API key in s
+func (c *Client) redactAPIKey(s string) string {
+	if c.config.APIKey == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, c.config.APIKey, maskSecret(c.config.APIKey))
+}
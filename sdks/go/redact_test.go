@@ -0,0 +1,59 @@
+package agentmem
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDebugLogsMaskCredentials(t *testing.T) {
+	const apiKey = "sk-live-0123456789abcdef"
+	logger := &recordingLogger{}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer "+apiKey {
+			t.Errorf("Authorization sent = %q, want the unmasked key", got)
+		}
+		// A server echoing the key back must not leak it into the log either
+		writeServerJSON(w, http.StatusOK, Memory{ID: "mem_1", AgentID: "agent", Content: "key " + apiKey})
+	})
+	config := NewConfig(apiKey).WithHTTPClient(NewTestHTTPClient(handler)).WithLogger(logger)
+	config.EnableCaching = false
+	config.CustomHeaders["X-Echo-Key"] = apiKey
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	memory, err := client.GetMemory(context.Background(), "mem_1")
+	if err != nil {
+		t.Fatalf("GetMemory: %v", err)
+	}
+	if !strings.Contains(memory.Content, apiKey) {
+		t.Errorf("response body was redacted for the caller: %q", memory.Content)
+	}
+
+	output := logger.output()
+	if !strings.Contains(output, "Bearer sk-l***") {
+		t.Errorf("log output has no masked Authorization header:\n%s", output)
+	}
+	if strings.Contains(output, apiKey) {
+		t.Errorf("log output contains the API key:\n%s", output)
+	}
+}
+
+func TestMaskSecret(t *testing.T) {
+	tests := []struct {
+		secret string
+		want   string
+	}{
+		{"", "***"},
+		{"short", "***"},
+		{"sk-live-0123456789abcdef", "sk-l***"},
+	}
+	for _, tt := range tests {
+		if got := maskSecret(tt.secret); got != tt.want {
+			t.Errorf("maskSecret(%q) = %q, want %q", tt.secret, got, tt.want)
+		}
+	}
+}
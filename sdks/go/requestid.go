@@ -0,0 +1,60 @@
+package agentmem
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+)
+
+// requestIDKey is the context key for caller-supplied request IDs
+type requestIDKey struct{}
+
+// WithRequestID returns a context that makes requests carry the given
+// correlation ID instead of a generated one
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the correlation ID set with WithRequestID
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(requestIDKey{}).(string)
+	return requestID, ok && requestID != ""
+}
+
+// requestID returns the correlation ID for a request, generating one when
+// the context does not carry it
+func (c *Client) requestID(ctx context.Context) string {
+	if requestID, ok := RequestIDFromContext(ctx); ok {
+		return requestID
+	}
+	if c.config.RequestIDGenerator != nil {
+		return c.config.RequestIDGenerator()
+	}
+	return newUUID()
+}
+
+// defaultRequestIDHeader is where the server's request ID is looked for
+// when request ID tagging is disabled
+const defaultRequestIDHeader = "X-Request-ID"
+
+// responseRequestID returns the request ID the server reported in a
+// response, or "" when it reported none
+func (c *Client) responseRequestID(header http.Header) string {
+	name := c.config.RequestIDHeader
+	if name == "" {
+		name = defaultRequestIDHeader
+	}
+	return header.Get(name)
+}
+
+// newUUID returns a random (version 4) UUID
+func newUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return ""
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
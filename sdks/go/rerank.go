@@ -0,0 +1,57 @@
+package agentmem
+
+import (
+	"context"
+)
+
+// Reranker reorders search results for a query, e.g. with a cross-encoder.
+// It may rescore, reorder, or drop results. The SDK ships no reranking
+// model; SearchMemoriesReranked only orchestrates the calls.
+type Reranker interface {
+	Rerank(ctx context.Context, query string, results []SearchResult) ([]SearchResult, error)
+}
+
+// SearchMemoriesReranked fetches the top candidates matches for query,
+// reranks them with the configured Reranker against the query's TextQuery
+// (or EmbedText), and returns the first query.Limit reranked results.
+// Fetching more candidates than query.Limit gives the reranker room to
+// promote results the server ranked lower; 0 fetches query.Limit.
+func (c *Client) SearchMemoriesReranked(ctx context.Context, query SearchQuery, candidates int) ([]SearchResult, error) {
+	if c.config.Reranker == nil {
+		return nil, NewValidationError("reranked search requires a Reranker in the client config")
+	}
+	if candidates < 0 {
+		return nil, NewValidationError("candidates must not be negative")
+	}
+	text := query.EmbedText
+	if query.TextQuery != nil && *query.TextQuery != "" {
+		text = *query.TextQuery
+	}
+	if text == "" {
+		return nil, NewValidationError("reranked search requires a text query or embed text")
+	}
+
+	limit := query.Limit
+	if candidates > limit {
+		query.Limit = candidates
+	}
+	results, err := c.SearchMemories(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	reranked, err := c.config.Reranker.Rerank(ctx, text, results)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, NewCancelledError(ctx.Err())
+		}
+		return nil, NewRerankError(err)
+	}
+	if limit > 0 && len(reranked) > limit {
+		reranked = reranked[:limit]
+	}
+	if reranked == nil {
+		reranked = []SearchResult{}
+	}
+	return reranked, nil
+}
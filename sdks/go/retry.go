@@ -0,0 +1,185 @@
+package agentmem
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// maxRetryAfterWait bounds how long a server-sent Retry-After can delay a
+// retry. resty caps every wait at its max wait time, so it is set to this
+// and the configured MaxRetryDelay is applied in retryAfter instead.
+const maxRetryAfterWait = 10 * time.Minute
+
+// shouldRetry reports whether a failed attempt is retried: network errors,
+// rate limiting, and server errors are; other API errors are not. Nothing
+// is retried once the request's context is done.
+func shouldRetry(r *resty.Response, err error) bool {
+	if r != nil && r.Request != nil && r.Request.Context().Err() != nil {
+		return false
+	}
+	if r != nil && r.RawResponse != nil {
+		return r.StatusCode() == http.StatusTooManyRequests || r.StatusCode() >= 500
+	}
+	// A rejected OAuth2 token request fails the same way on every attempt
+	var authErr *AuthenticationError
+	if errors.As(err, &authErr) {
+		return false
+	}
+	return err != nil
+}
+
+// retryBudgetCapacity is the number of retries a retry budget holds, and
+// starts with, so that clients sending few requests can still retry
+const retryBudgetCapacity = 10
+
+// retryBudget limits retries across a client to a fraction of its
+// requests. Each request deposits ratio tokens, up to retryBudgetCapacity,
+// and each retry withdraws one.
+type retryBudget struct {
+	ratio float64
+
+	mu     sync.Mutex
+	tokens float64
+}
+
+// newRetryBudget returns a retry budget, or nil when ratio is zero and
+// retries are not budgeted
+func newRetryBudget(ratio float64) *retryBudget {
+	if ratio <= 0 {
+		return nil
+	}
+	return &retryBudget{ratio: ratio, tokens: retryBudgetCapacity}
+}
+
+// deposit credits the budget for a new request
+func (b *retryBudget) deposit() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = math.Min(b.tokens+b.ratio, retryBudgetCapacity)
+}
+
+// withdraw reports whether a retry fits in the budget, spending it if so
+func (b *retryBudget) withdraw() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// shouldRetryWithinBudget reports whether a failed attempt is retried, as
+// shouldRetry, as long as the retry budget allows it
+func (c *Client) shouldRetryWithinBudget(r *resty.Response, err error) bool {
+	if !shouldRetry(r, err) {
+		return false
+	}
+	if !c.budget.withdraw() {
+		c.logger.Debugf("Not retrying: retry budget exhausted")
+		return false
+	}
+	return true
+}
+
+// retryAfter computes the wait before the next retry attempt. A Retry-After
+// header on a 429 or 503 response is honored; otherwise the wait follows the
+// configured backoff strategy.
+func (c *Client) retryAfter(client *resty.Client, resp *resty.Response) (time.Duration, error) {
+	if resp == nil || resp.Request == nil {
+		return 0, nil
+	}
+
+	if wait, ok := responseRetryAfter(resp); ok {
+		// resty treats zero as "use the default backoff"
+		if wait <= 0 {
+			wait = time.Nanosecond
+		}
+		return wait, nil
+	}
+
+	backoff := c.config.GetBackoff()
+	delay := c.config.RetryDelay
+	if backoff != BackoffFixed {
+		delay = exponentialDelay(c.config.RetryDelay, c.config.GetMaxRetryDelay(), resp.Request.Attempt-1)
+	}
+	if delay <= 0 {
+		return time.Nanosecond, nil
+	}
+
+	switch backoff {
+	case BackoffFixed, BackoffExponential:
+		return delay, nil
+	case BackoffExponentialJitter:
+		// Full jitter: random between 0 and the capped exponential delay
+		// https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/
+		return time.Duration(rand.Int63n(int64(delay))) + time.Nanosecond, nil
+	default:
+		// Equal jitter, matching resty's default backoff
+		half := delay / 2
+		return half + time.Duration(rand.Int63n(int64(half)+1)), nil
+	}
+}
+
+// responseRetryAfter returns the wait requested by a 429 or 503 response
+func responseRetryAfter(resp *resty.Response) (time.Duration, bool) {
+	if resp.RawResponse == nil {
+		return 0, false
+	}
+	if resp.StatusCode() != http.StatusTooManyRequests && resp.StatusCode() != http.StatusServiceUnavailable {
+		return 0, false
+	}
+	return parseRetryAfter(resp.Header().Get("Retry-After"), time.Now())
+}
+
+// parseRetryAfter parses a Retry-After header value in either its
+// delay-seconds or HTTP-date form
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := date.Sub(now)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// exponentialDelay returns base*2^attempt, capped at maxDelay
+func exponentialDelay(base, maxDelay time.Duration, attempt int) time.Duration {
+	if attempt < 0 {
+		attempt = 0
+	}
+	delay := base
+	for i := 0; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
@@ -0,0 +1,84 @@
+package agentmem
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCancellationStopsRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		respond    func(w http.ResponseWriter, r *http.Request)
+		retryDelay time.Duration
+	}{
+		{
+			name: "during a slow request",
+			respond: func(w http.ResponseWriter, r *http.Request) {
+				<-r.Context().Done()
+			},
+			retryDelay: time.Millisecond,
+		},
+		{
+			name: "during backoff",
+			respond: func(w http.ResponseWriter, r *http.Request) {
+				writeServerError(w, http.StatusServiceUnavailable, "unavailable")
+			},
+			retryDelay: time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			client, _ := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&attempts, 1)
+				tt.respond(w, r)
+			}), func(config *Config) {
+				config.MaxRetries = 3
+				config.RetryDelay = tt.retryDelay
+				config.MaxRetryDelay = tt.retryDelay
+				config.Backoff = BackoffFixed
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(50*time.Millisecond, cancel)
+			start := time.Now()
+			_, err := client.GetMemory(ctx, "mem_1")
+			elapsed := time.Since(start)
+
+			var cancelled *CancelledError
+			if !errors.As(err, &cancelled) || !errors.Is(err, context.Canceled) {
+				t.Fatalf("error = %T %v, want *CancelledError wrapping context.Canceled", err, err)
+			}
+			if elapsed > 5*time.Second {
+				t.Errorf("GetMemory returned after %v, want promptly after cancellation", elapsed)
+			}
+			// Give a retry that should not happen the chance to arrive
+			time.Sleep(50 * time.Millisecond)
+			if got := atomic.LoadInt32(&attempts); got != 1 {
+				t.Errorf("server saw %d attempts, want 1", got)
+			}
+		})
+	}
+}
+
+func TestDoneContextFailsFast(t *testing.T) {
+	var attempts int32
+	client := newHandlerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		writeServerJSON(w, http.StatusOK, Memory{ID: "mem_1"})
+	}), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := client.GetMemory(ctx, "mem_1")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want context.Canceled", err)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 0 {
+		t.Errorf("server saw %d attempts, want none", got)
+	}
+}
@@ -0,0 +1,48 @@
+package agentmem
+
+import (
+	"context"
+	"fmt"
+)
+
+// ImportanceScorer assigns an importance in [0, 1] to a memory from its
+// content and metadata, e.g. with heuristics on length, keywords, or
+// recency. The SDK ships no scorer; every write that creates memories
+// consults the configured one for memories without an Importance or
+// ImportanceLevel.
+type ImportanceScorer interface {
+	Score(ctx context.Context, content string, metadata map[string]interface{}) (float64, error)
+}
+
+// scoreImportance sets the importance of params from the configured scorer.
+// Params that already carry an importance are returned unchanged. The score
+// is used as is; ImportanceScaler applies only to caller-supplied values.
+func (c *Client) scoreImportance(ctx context.Context, params CreateMemoryParams) (CreateMemoryParams, error) {
+	if c.config.ImportanceScorer == nil || params.Importance != nil {
+		return params, nil
+	}
+
+	score, err := c.config.ImportanceScorer.Score(ctx, params.Content, params.Metadata)
+	if err != nil {
+		if ctx.Err() != nil {
+			return params, NewCancelledError(ctx.Err())
+		}
+		return params, NewScoringError(err)
+	}
+	if validateImportance("score", score) != nil {
+		return params, NewScoringError(fmt.Errorf("scorer returned %v, outside [0, 1]", score))
+	}
+	params.Importance = &score
+	return params, nil
+}
+
+// fillImportance gives params without an importance one from the
+// configured scorer or, failing that, from DefaultImportanceByType. Every
+// write that creates memories applies it after prepareCreateParams.
+func (c *Client) fillImportance(ctx context.Context, params CreateMemoryParams) (CreateMemoryParams, error) {
+	params, err := c.scoreImportance(ctx, params)
+	if err != nil {
+		return params, err
+	}
+	return c.defaultImportance(params), nil
+}
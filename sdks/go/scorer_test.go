@@ -0,0 +1,174 @@
+package agentmem
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// lengthScorer scores a memory by the length of its content, capped at 1
+type lengthScorer struct {
+	calls int32
+}
+
+func (s *lengthScorer) Score(ctx context.Context, content string, metadata map[string]interface{}) (float64, error) {
+	atomic.AddInt32(&s.calls, 1)
+	return min(float64(len(content))/10, 1), nil
+}
+
+// storedImportance returns the importance of each stored memory by content
+func storedImportance(server *MemoryServer) map[string]float64 {
+	importance := make(map[string]float64)
+	for _, memory := range server.Memories() {
+		importance[memory.Content] = memory.Importance
+	}
+	return importance
+}
+
+func TestImportanceAppliedToEveryCreate(t *testing.T) {
+	explicit := 0.9
+	semantic := MemoryTypeSemantic
+	memories := []CreateMemoryParams{
+		{AgentID: "agent", Content: "abc"},
+		{AgentID: "agent", Content: "abcdef", Importance: &explicit},
+		{AgentID: "agent", Content: "abcdefgh", MemoryType: &semantic},
+	}
+
+	tests := []struct {
+		name   string
+		create func(ctx context.Context, client *Client) error
+	}{
+		{"AddMemory", func(ctx context.Context, client *Client) error {
+			for _, memory := range memories {
+				if _, err := client.AddMemory(ctx, memory); err != nil {
+					return err
+				}
+			}
+			return nil
+		}},
+		{"BatchAddMemories", func(ctx context.Context, client *Client) error {
+			_, err := client.BatchAddMemories(ctx, BatchCreateMemoryParams{Memories: memories})
+			return err
+		}},
+		{"ImportMemories", func(ctx context.Context, client *Client) error {
+			input := `[{"agent_id":"agent","content":"abc"},` +
+				`{"agent_id":"agent","content":"abcdef","importance":0.9},` +
+				`{"agent_id":"agent","content":"abcdefgh","memory_type":"semantic"}]`
+			_, err := client.ImportMemories(ctx, strings.NewReader(input), ImportOptions{})
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+" with a scorer", func(t *testing.T) {
+			server := NewMemoryServer()
+			scorer := &lengthScorer{}
+			client := newHandlerClient(t, server, func(config *Config) {
+				config.ImportanceScorer = scorer
+				config.DefaultImportanceByType = map[MemoryType]float64{MemoryTypeSemantic: 0.1}
+			})
+			if err := tt.create(context.Background(), client); err != nil {
+				t.Fatalf("create: %v", err)
+			}
+			want := map[string]float64{"abc": 0.3, "abcdef": 0.9, "abcdefgh": 0.8}
+			if got := storedImportance(server); !importanceEqual(got, want) {
+				t.Errorf("stored importance = %v, want %v", got, want)
+			}
+			if calls := atomic.LoadInt32(&scorer.calls); calls != 2 {
+				t.Errorf("scorer called %d times, want 2", calls)
+			}
+		})
+		t.Run(tt.name+" with type defaults", func(t *testing.T) {
+			server := NewMemoryServer()
+			client := newHandlerClient(t, server, func(config *Config) {
+				config.DefaultImportanceByType = map[MemoryType]float64{MemoryTypeSemantic: 0.1}
+			})
+			if err := tt.create(context.Background(), client); err != nil {
+				t.Fatalf("create: %v", err)
+			}
+			// The untyped memory gets the server default
+			want := map[string]float64{"abc": 0.5, "abcdef": 0.9, "abcdefgh": 0.1}
+			if got := storedImportance(server); !importanceEqual(got, want) {
+				t.Errorf("stored importance = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestBatchAddMemoriesScoresOnlyValidBatches(t *testing.T) {
+	scorer := &lengthScorer{}
+	client := newHandlerClient(t, NewMemoryServer(), func(config *Config) {
+		config.ImportanceScorer = scorer
+	})
+
+	_, err := client.BatchAddMemories(context.Background(), BatchCreateMemoryParams{Memories: []CreateMemoryParams{
+		{AgentID: "agent", Content: "valid"},
+		{AgentID: "agent"},
+	}})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Errorf("error = %v, want *ValidationError", err)
+	}
+	if calls := atomic.LoadInt32(&scorer.calls); calls != 0 {
+		t.Errorf("scorer called %d times for an invalid batch, want 0", calls)
+	}
+}
+
+func TestExecuteBatchScoresCreates(t *testing.T) {
+	var request BatchRequest
+	scorer := &lengthScorer{}
+	client := newHandlerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeServerError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		results := make([]BatchOpResult, len(request.Operations))
+		for i := range results {
+			results[i] = BatchOpResult{Success: true}
+		}
+		writeServerJSON(w, http.StatusOK, BatchResponse{Results: results})
+	}), func(config *Config) {
+		config.ImportanceScorer = scorer
+	})
+
+	explicit := 0.9
+	_, err := client.ExecuteBatch(context.Background(), []BatchOp{
+		NewCreateOp(CreateMemoryParams{AgentID: "agent", Content: "abcd"}),
+		NewDeleteOp("m1"),
+		NewCreateOp(CreateMemoryParams{AgentID: "agent", Content: "abcdef", Importance: &explicit}),
+	}, false)
+	if err != nil {
+		t.Fatalf("ExecuteBatch: %v", err)
+	}
+
+	ops := request.Operations
+	if len(ops) != 3 || ops[0].Create == nil || ops[2].Create == nil {
+		t.Fatalf("sent operations = %+v, want two creates around a delete", ops)
+	}
+	if got := ops[0].Create.Importance; got == nil || *got < 0.4-1e-9 || *got > 0.4+1e-9 {
+		t.Errorf("scored create importance = %v, want 0.4", got)
+	}
+	if got := ops[2].Create.Importance; got == nil || *got != explicit {
+		t.Errorf("explicit create importance = %v, want %v", got, explicit)
+	}
+	if calls := atomic.LoadInt32(&scorer.calls); calls != 1 {
+		t.Errorf("scorer called %d times, want 1", calls)
+	}
+}
+
+// importanceEqual compares importance maps within floating point tolerance
+func importanceEqual(got, want map[string]float64) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for key, wantValue := range want {
+		value, ok := got[key]
+		if !ok || value < wantValue-1e-9 || value > wantValue+1e-9 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,195 @@
+package agentmem
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSearchMemoriesEmptyResultsVersusUndecodableBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		wantResults int
+		wantErr     interface{}
+	}{
+		{name: "empty results", status: http.StatusOK, body: `{"results":[]}`},
+		{name: "null results", status: http.StatusOK, body: `{"results":null}`},
+		{name: "one result", status: http.StatusOK, body: `{"results":[{"memory":{"id":"mem_1"},"score":0.9}]}`, wantResults: 1},
+		{name: "empty body", status: http.StatusOK, body: ``, wantErr: new(*DecodeError)},
+		{name: "malformed body", status: http.StatusOK, body: `{"results":`, wantErr: new(*DecodeError)},
+		{name: "server error with empty body", status: http.StatusInternalServerError, body: ``, wantErr: new(*ServerError)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newHandlerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}), func(config *Config) {
+				config.MaxRetries = 0
+			})
+
+			text := "coffee"
+			results, err := client.SearchMemories(context.Background(), SearchQuery{AgentID: "agent", TextQuery: &text, Limit: 10})
+			if tt.wantErr != nil {
+				if !errors.As(err, tt.wantErr) {
+					t.Fatalf("error = %T %v, want %T", err, err, tt.wantErr)
+				}
+				if results != nil {
+					t.Errorf("results = %v, want nil alongside the error", results)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SearchMemories: %v", err)
+			}
+			if results == nil {
+				t.Error("results = nil, want a non-nil slice for a successful search")
+			}
+			if len(results) != tt.wantResults {
+				t.Errorf("len(results) = %d, want %d", len(results), tt.wantResults)
+			}
+		})
+	}
+}
+
+func TestFilteredVectorSearchSendsFiltersWithVector(t *testing.T) {
+	memoryType := MemoryTypeProcedural
+	minImportance := 0.8
+	createdAfter := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	filtered := SearchQuery{
+		AgentID:         "agent",
+		MemoryType:      &memoryType,
+		MinImportance:   &minImportance,
+		CreatedAfter:    &createdAfter,
+		MetadataFilters: map[string]interface{}{"topic": "go"},
+		Limit:           5,
+	}
+	wantFilters := map[string]interface{}{
+		"agent_id":         "agent",
+		"memory_type":      "procedural",
+		"min_importance":   0.8,
+		"created_after":    "2026-01-01T00:00:00Z",
+		"advanced_filters": []interface{}{map[string]interface{}{"field": "topic", "operator": "eq", "value": "go"}},
+		"limit":            float64(5),
+	}
+
+	stale := "ignored"
+	tests := []struct {
+		name     string
+		search   func(*Client) ([]SearchResult, error)
+		wantText interface{}
+	}{
+		{
+			name: "vector",
+			search: func(client *Client) ([]SearchResult, error) {
+				query := filtered
+				query.TextQuery = &stale
+				return client.SearchByVector(context.Background(), []float64{0.1, 0.2}, query)
+			},
+		},
+		{
+			name: "hybrid",
+			search: func(client *Client) ([]SearchResult, error) {
+				return client.HybridSearch(context.Background(), "deploy", []float64{0.1, 0.2}, filtered)
+			},
+			wantText: "deploy",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body map[string]interface{}
+			client := newHandlerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("decode request: %v", err)
+				}
+				writeServerJSON(w, http.StatusOK, SearchResponse{Results: []SearchResult{}})
+			}), nil)
+
+			if _, err := tt.search(client); err != nil {
+				t.Fatalf("search: %v", err)
+			}
+			if got := body["vector_query"]; !reflect.DeepEqual(got, []interface{}{0.1, 0.2}) {
+				t.Errorf("vector_query = %v, want [0.1 0.2]", got)
+			}
+			if got := body["text_query"]; got != tt.wantText {
+				t.Errorf("text_query = %v, want %v", got, tt.wantText)
+			}
+			for field, want := range wantFilters {
+				if got := body[field]; !reflect.DeepEqual(got, want) {
+					t.Errorf("%s = %#v, want %#v", field, got, want)
+				}
+			}
+			if _, ok := body["metadata_filters"]; ok {
+				t.Errorf("metadata_filters sent alongside advanced_filters: %v", body["metadata_filters"])
+			}
+		})
+	}
+}
+
+func TestSearchSimilarFetchesSourceEmbedding(t *testing.T) {
+	var searched map[string]interface{}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/memories/src":
+			// Like the server, omit the embedding unless it is asked for
+			source := Memory{ID: "src", AgentID: "agent", Content: "source"}
+			if r.URL.Query().Get("include_embedding") == "true" {
+				source.Embedding = []float64{0.1, 0.2}
+			}
+			writeServerJSON(w, http.StatusOK, source)
+		case "/api/v1/memories/search":
+			if err := json.NewDecoder(r.Body).Decode(&searched); err != nil {
+				t.Errorf("decode search: %v", err)
+			}
+			writeServerJSON(w, http.StatusOK, SearchResponse{Results: []SearchResult{
+				{Memory: Memory{ID: "src"}, Score: 1},
+				{Memory: Memory{ID: "a"}, Score: 0.9},
+				{Memory: Memory{ID: "b"}, Score: 0.8},
+			}})
+		default:
+			writeServerError(w, http.StatusNotFound, "not found")
+		}
+	})
+	client := newHandlerClient(t, handler, func(config *Config) {
+		config.EnableCaching = true
+	})
+	ctx := context.Background()
+
+	// Cache a projected copy of the source first
+	if _, err := client.GetMemoryWithOptions(ctx, "src", RequestOptions{}); err != nil {
+		t.Fatalf("GetMemoryWithOptions: %v", err)
+	}
+
+	results, err := client.SearchSimilar(ctx, "src", 1)
+	if err != nil {
+		t.Fatalf("SearchSimilar: %v", err)
+	}
+	if len(results) != 1 || results[0].Memory.ID != "a" {
+		t.Errorf("results = %+v, want only memory a", results)
+	}
+	if got := searched["vector_query"]; !reflect.DeepEqual(got, []interface{}{0.1, 0.2}) {
+		t.Errorf("vector_query = %v, want the source embedding", got)
+	}
+	if got := searched["agent_id"]; got != "agent" {
+		t.Errorf("agent_id = %v, want the source agent", got)
+	}
+}
+
+func TestSearchSimilarWithoutEmbedding(t *testing.T) {
+	client := newHandlerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeServerJSON(w, http.StatusOK, Memory{ID: "src", AgentID: "agent"})
+	}), nil)
+
+	_, err := client.SearchSimilar(context.Background(), "src", 5)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("error = %T %v, want *ValidationError", err, err)
+	}
+}
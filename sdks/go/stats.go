@@ -0,0 +1,114 @@
+package agentmem
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// clientStats holds client-side request counters. All counters are updated
+// atomically so recording them on the request path is lock-free.
+type clientStats struct {
+	requests          int64
+	retries           int64
+	bytesSent         int64
+	bytesReceived     int64
+	latencyNanos      int64
+	retriesByEndpoint sync.Map // endpoint -> *int64
+}
+
+// recordResponse records a completed HTTP attempt
+func (s *clientStats) recordResponse(resp *resty.Response) {
+	atomic.AddInt64(&s.requests, 1)
+	atomic.AddInt64(&s.bytesReceived, resp.Size())
+	atomic.AddInt64(&s.latencyNanos, int64(resp.Time()))
+	if resp.Request != nil && resp.Request.RawRequest != nil && resp.Request.RawRequest.ContentLength > 0 {
+		atomic.AddInt64(&s.bytesSent, resp.Request.RawRequest.ContentLength)
+	}
+}
+
+// recordRetry records a retry of the given endpoint
+func (s *clientStats) recordRetry(endpoint string) {
+	atomic.AddInt64(&s.retries, 1)
+	if endpoint == "" {
+		return
+	}
+	counter, ok := s.retriesByEndpoint.Load(endpoint)
+	if !ok {
+		counter, _ = s.retriesByEndpoint.LoadOrStore(endpoint, new(int64))
+	}
+	atomic.AddInt64(counter.(*int64), 1)
+}
+
+// snapshot returns a point-in-time copy of the counters
+func (s *clientStats) snapshot() ClientStats {
+	stats := ClientStats{
+		Requests:          atomic.LoadInt64(&s.requests),
+		Retries:           atomic.LoadInt64(&s.retries),
+		RetriesByEndpoint: make(map[string]int64),
+		BytesSent:         atomic.LoadInt64(&s.bytesSent),
+		BytesReceived:     atomic.LoadInt64(&s.bytesReceived),
+		TotalLatency:      time.Duration(atomic.LoadInt64(&s.latencyNanos)),
+	}
+	if stats.Requests > 0 {
+		stats.AverageLatency = stats.TotalLatency / time.Duration(stats.Requests)
+	}
+	s.retriesByEndpoint.Range(func(key, value interface{}) bool {
+		stats.RetriesByEndpoint[key.(string)] = atomic.LoadInt64(value.(*int64))
+		return true
+	})
+	return stats
+}
+
+// onRetry is the resty retry hook that feeds the retry counters
+func (c *Client) onRetry(retries int, resp *resty.Response, err error) {
+	var endpoint string
+	if resp != nil && resp.Request != nil {
+		// resty runs retry hooks after the final attempt too, when no
+		// further retry will actually be made
+		if resp.Request.Attempt > retries {
+			return
+		}
+		route := c.requestRoute(resp.Request.URL)
+		endpoint = resp.Request.Method + " " + route
+		c.metrics.recordRetry(resp.Request.Method, route)
+		c.logger.Warnf("Retrying %s after attempt %d: %v", endpoint, resp.Request.Attempt, retryReason(resp, err))
+	}
+	c.stats.recordRetry(endpoint)
+}
+
+// requestRoute returns the route of a request URL for per-endpoint
+// counters: the endpoint without the base URL and query string, with any
+// memory ID replaced by a placeholder so that the number of keys stays
+// bounded
+func (c *Client) requestRoute(rawURL string) string {
+	path := strings.TrimPrefix(rawURL, c.httpClient.BaseURL)
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	return endpointRoute(path)
+}
+
+// retryReason describes why an attempt is retried
+func retryReason(resp *resty.Response, err error) interface{} {
+	if err != nil {
+		return err
+	}
+	return resp.Status()
+}
+
+// logResponse logs a completed HTTP attempt with its timing breakdown
+func (c *Client) logResponse(resp *resty.Response) {
+	endpoint := resp.Request.Method + " " + strings.TrimPrefix(resp.Request.URL, c.httpClient.BaseURL)
+	trace := resp.Request.TraceInfo()
+	c.logger.Debugf("%s -> %d in %s (dns %s, connect %s, server %s, attempt %d)",
+		endpoint, resp.StatusCode(), resp.Time(), trace.DNSLookup, trace.ConnTime, trace.ServerTime, resp.Request.Attempt)
+}
+
+// ClientStats returns client-side request statistics, including retry counts
+func (c *Client) ClientStats() ClientStats {
+	return c.stats.snapshot()
+}
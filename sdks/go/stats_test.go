@@ -0,0 +1,68 @@
+package agentmem
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestClientStatsRetriesByEndpointUseRoutes(t *testing.T) {
+	var mu sync.Mutex
+	attempts := make(map[string]int)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts[r.URL.String()]++
+		first := attempts[r.URL.String()] == 1
+		mu.Unlock()
+		if first {
+			writeServerError(w, http.StatusServiceUnavailable, "unavailable")
+			return
+		}
+		writeServerJSON(w, http.StatusOK, Memory{ID: "id", AgentID: "agent"})
+	})
+	client := newHandlerClient(t, handler, func(config *Config) {
+		config.MaxRetries = 1
+	})
+
+	ctx := context.Background()
+	for _, id := range []string{"mem_1", "mem_2"} {
+		if _, err := client.GetMemory(ctx, id); err != nil {
+			t.Fatalf("GetMemory(%s): %v", id, err)
+		}
+	}
+	if _, err := client.GetMemoryWithOptions(ctx, "mem_3", RequestOptions{}); err != nil {
+		t.Fatalf("GetMemoryWithOptions: %v", err)
+	}
+
+	stats := client.ClientStats()
+	if stats.Retries != 3 {
+		t.Errorf("Retries = %d, want 3", stats.Retries)
+	}
+	want := map[string]int64{"GET /memories/{id}": 3}
+	if len(stats.RetriesByEndpoint) != len(want) || stats.RetriesByEndpoint["GET /memories/{id}"] != 3 {
+		t.Errorf("RetriesByEndpoint = %v, want %v", stats.RetriesByEndpoint, want)
+	}
+}
+
+func TestRequestRoute(t *testing.T) {
+	client := newHandlerClient(t, http.NotFoundHandler(), nil)
+	base := client.httpClient.BaseURL
+
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{base + "/memories", "/memories"},
+		{base + "/memories/mem_1", "/memories/{id}"},
+		{base + "/memories/mem_1?exclude_embedding=true", "/memories/{id}"},
+		{base + "/memories/stats?agent_id=agent", "/memories/stats"},
+		{base + "/memories/mem_1/tags", "/memories/{id}/tags"},
+		{base + "/memories/batch/get", "/memories/batch/get"},
+	}
+	for _, tt := range tests {
+		if got := client.requestRoute(tt.url); got != tt.want {
+			t.Errorf("requestRoute(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package agentmem
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxSSEEventSize bounds a single line of a Server-Sent Events stream
+const maxSSEEventSize = 16 * 1024 * 1024
+
+// errStopStream ends reading a stream early without an error
+var errStopStream = errors.New("stop stream")
+
+// openStream starts a long-lived streaming request and returns the response
+// once its headers have arrived. The unary Timeout bounds only this
+// handshake; afterwards the stream lives until ctx is done or, when set,
+// StreamTimeout elapses. The returned cancel function must be called once
+// the caller is done with the response body.
+func (c *Client) openStream(ctx context.Context, method, endpoint string, body interface{}) (*http.Response, context.CancelFunc, error) {
+	if err := c.waitForRateLimit(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	var streamCtx context.Context
+	var cancel context.CancelFunc
+	if c.config.StreamTimeout > 0 {
+		streamCtx, cancel = context.WithTimeout(ctx, c.config.StreamTimeout)
+	} else {
+		streamCtx, cancel = context.WithCancel(ctx)
+	}
+
+	var reader io.Reader
+	if body != nil {
+		bodyBytes, err := c.marshalJSON(body)
+		if err != nil {
+			cancel()
+			return nil, nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(bodyBytes)
+	}
+
+	req, err := http.NewRequestWithContext(streamCtx, method, c.config.GetAPIBaseURL()+endpoint, reader)
+	if err != nil {
+		cancel()
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, value := range c.config.GetDefaultHeaders() {
+		req.Header.Set(key, value)
+	}
+	// Let the transport negotiate and transparently decode compression
+	req.Header.Del("Accept-Encoding")
+	req.Header.Set("Accept", "text/event-stream")
+	var requestID string
+	if c.config.RequestIDHeader != "" {
+		requestID = c.requestID(ctx)
+		req.Header.Set(c.config.RequestIDHeader, requestID)
+	}
+
+	if err := c.authorize(req); err != nil {
+		cancel()
+		return nil, nil, withRequestID(err, requestID)
+	}
+	c.runRequestHooks(req)
+
+	if err := c.breaker.allow(); err != nil {
+		cancel()
+		return nil, nil, err
+	}
+
+	handshake := time.AfterFunc(c.config.Timeout, cancel)
+	resp, err := c.streamClient.Do(req)
+	handshake.Stop()
+	if err != nil {
+		cancel()
+		err = withRequestID(requestError(ctx, err), requestID)
+		c.breaker.record(err)
+		return nil, nil, err
+	}
+
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		defer cancel()
+		respBody, _ := io.ReadAll(resp.Body)
+		err = handleHTTPError(resp.StatusCode, errorMessage(resp.StatusCode, resp.Status, respBody))
+		err = withRequestID(withRequestID(err, c.responseRequestID(resp.Header)), requestID)
+		c.breaker.record(err)
+		return nil, nil, err
+	}
+
+	c.breaker.record(nil)
+	return resp, cancel, nil
+}
+
+// sseEvent is a single Server-Sent Event
+type sseEvent struct {
+	Event string
+	Data  []byte
+}
+
+// readSSE parses Server-Sent Events from r and passes each to fn until the
+// stream ends or fn returns an error. fn returning errStopStream ends
+// reading without an error.
+func readSSE(r io.Reader, fn func(sseEvent) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxSSEEventSize)
+
+	var event sseEvent
+	var data bytes.Buffer
+	dispatch := func() error {
+		if data.Len() == 0 {
+			event = sseEvent{}
+			return nil
+		}
+		event.Data = bytes.TrimSuffix(data.Bytes(), []byte("\n"))
+		if event.Event == "" {
+			event.Event = "message"
+		}
+		err := fn(event)
+		event = sseEvent{}
+		data.Reset()
+		return err
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if err := dispatch(); err != nil {
+				if err == errStopStream {
+					return nil
+				}
+				return err
+			}
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue // comment, e.g. a keep-alive
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event.Event = value
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	// A final event without a trailing blank line is incomplete and dropped
+	return nil
+}
+
+// SearchMemoriesStream searches for memories over Server-Sent Events,
+// emitting results as the server produces them instead of buffering the
+// whole result set. The results channel is closed once the search is done;
+// the error channel then yields at most one error and is closed as well.
+// Cancel ctx to stop early.
+//
+//	results, errs := client.SearchMemoriesStream(ctx, query)
+//	for result := range results {
+//		// use result
+//	}
+//	if err := <-errs; err != nil {
+//		// handle error
+//	}
+func (c *Client) SearchMemoriesStream(ctx context.Context, query SearchQuery) (<-chan SearchResult, <-chan error) {
+	results := make(chan SearchResult)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(results)
+		if err := c.streamSearch(ctx, query, results); err != nil {
+			errs <- err
+		}
+	}()
+
+	return results, errs
+}
+
+// streamSearch performs a streaming search, sending results until the
+// server signals the end of the stream
+func (c *Client) streamSearch(ctx context.Context, query SearchQuery, results chan<- SearchResult) error {
+	if err := query.Validate(); err != nil {
+		return err
+	}
+	query, err := c.embedQuery(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	resp, cancel, err := c.openStream(ctx, "POST", "/memories/search/stream", query.wireQuery())
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	defer resp.Body.Close()
+	requestID := resp.Request.Header.Get(c.config.RequestIDHeader)
+
+	err = readSSE(resp.Body, func(event sseEvent) error {
+		switch event.Event {
+		case "message", "result":
+			var result SearchResult
+			if err := c.unmarshalJSON(event.Data, &result); err != nil {
+				return NewDecodeError(resp.StatusCode, err)
+			}
+			if query.Highlight && query.TextQuery != nil {
+				highlightResult(*query.TextQuery, &result)
+			}
+			select {
+			case results <- result:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		case "error":
+			message := strings.TrimSpace(string(event.Data))
+			var apiResp APIResponse
+			if json.Unmarshal(event.Data, &apiResp) == nil && apiResp.Error != nil {
+				message = *apiResp.Error
+			}
+			return NewServerError(message)
+		case "done":
+			return errStopStream
+		}
+		return nil
+	})
+	if err != nil {
+		return withRequestID(requestError(ctx, err), requestID)
+	}
+	return nil
+}
@@ -0,0 +1,281 @@
+package agentmem
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// subscribePingInterval is how often a subscription pings the server
+	subscribePingInterval = 30 * time.Second
+	// subscribeReadTimeout is how long a subscription waits for any frame,
+	// including a pong, before it treats the connection as dead
+	subscribeReadTimeout = 2 * subscribePingInterval
+	// subscribeMaxBackoff caps the wait between reconnection attempts
+	subscribeMaxBackoff = 30 * time.Second
+)
+
+// MemoryEventType identifies the change reported by a MemoryEvent
+type MemoryEventType string
+
+const (
+	// MemoryCreated reports a newly created memory
+	MemoryCreated MemoryEventType = "created"
+	// MemoryUpdated reports a change to an existing memory
+	MemoryUpdated MemoryEventType = "updated"
+	// MemoryDeleted reports a deleted memory
+	MemoryDeleted MemoryEventType = "deleted"
+)
+
+// MemoryEvent is a change to one of an agent's memories
+type MemoryEvent struct {
+	Type MemoryEventType `json:"type"`
+	// Memory is the memory after the change. For a deleted memory, the
+	// server may send only its ID and agent ID.
+	Memory    Memory    `json:"memory"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Subscribe opens a WebSocket to the server and returns a channel of
+// changes to an agent's memories as they happen. The channel is closed once
+// ctx is done or the client is closed, or when the connection fails with an error that reconnecting
+// cannot fix, such as an authentication failure.
+//
+// A dropped connection is reopened with exponential backoff, starting at
+// Config.RetryDelay. Changes made while the subscription is reconnecting are
+// not delivered. Events for updated and deleted memories drop them from the
+// client's cache.
+//
+// The connection is dialed by the client itself: of a custom HTTPClient,
+// only the proxy, TLS and dial settings of an *http.Transport are used.
+func (c *Client) Subscribe(ctx context.Context, agentID string) (<-chan MemoryEvent, error) {
+	if agentID == "" {
+		return nil, NewValidationError("agent ID is required")
+	}
+
+	ctx, cancel, err := c.backgroundContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := c.dialSubscription(ctx, agentID)
+	if err != nil {
+		cancel()
+		c.background.Done()
+		return nil, err
+	}
+
+	events := make(chan MemoryEvent)
+	go func() {
+		defer c.background.Done()
+		defer cancel()
+		c.runSubscription(ctx, agentID, conn, events)
+	}()
+	return events, nil
+}
+
+// runSubscription delivers events from conn, reconnecting whenever the
+// connection drops, until ctx is done or reconnecting fails permanently
+func (c *Client) runSubscription(ctx context.Context, agentID string, conn *websocket.Conn, events chan<- MemoryEvent) {
+	defer close(events)
+
+	for {
+		err := c.readSubscription(ctx, agentID, conn, events)
+		if ctx.Err() != nil {
+			return
+		}
+		c.logger.Warnf("Subscription for agent %s dropped: %v", agentID, err)
+
+		conn, err = c.reconnectSubscription(ctx, agentID)
+		if err != nil {
+			if ctx.Err() == nil {
+				c.logger.Warnf("Subscription for agent %s stopped: %v", agentID, err)
+			}
+			return
+		}
+	}
+}
+
+// reconnectSubscription dials the subscription again, backing off between
+// attempts, until it succeeds, ctx is done, or the error is not temporary
+func (c *Client) reconnectSubscription(ctx context.Context, agentID string) (*websocket.Conn, error) {
+	base := c.config.RetryDelay
+	if base <= 0 {
+		base = time.Second
+	}
+	for attempt := 0; ; attempt++ {
+		timer := time.NewTimer(exponentialDelay(base, subscribeMaxBackoff, attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, NewCancelledError(ctx.Err())
+		case <-timer.C:
+		}
+
+		conn, err := c.dialSubscription(ctx, agentID)
+		if err == nil {
+			return conn, nil
+		}
+		if !isTemporaryError(err) {
+			return nil, err
+		}
+		c.logger.Debugf("Reconnecting subscription for agent %s after attempt %d: %v", agentID, attempt+1, err)
+	}
+}
+
+// readSubscription delivers events from conn until the connection fails or
+// ctx is done, then closes it. It also pings the server so that a dead
+// connection is noticed.
+func (c *Client) readSubscription(ctx context.Context, agentID string, conn *websocket.Conn, events chan<- MemoryEvent) error {
+	done := make(chan struct{})
+	defer close(done)
+	defer conn.Close()
+
+	extendDeadline := func() {
+		conn.SetReadDeadline(time.Now().Add(subscribeReadTimeout))
+	}
+	extendDeadline()
+	conn.SetPongHandler(func(string) error {
+		extendDeadline()
+		return nil
+	})
+	conn.SetPingHandler(func(data string) error {
+		extendDeadline()
+		err := conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+		if errors.Is(err, websocket.ErrCloseSent) {
+			return nil
+		}
+		return err
+	})
+
+	go func() {
+		ticker := time.NewTicker(subscribePingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				closing := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+				conn.WriteControl(websocket.CloseMessage, closing, time.Now().Add(time.Second))
+				conn.Close()
+				return
+			case <-ticker.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(c.config.Timeout)); err != nil {
+					conn.Close()
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		extendDeadline()
+
+		var event MemoryEvent
+		if err := c.unmarshalJSON(data, &event); err != nil {
+			c.logger.Warnf("Skipping malformed event for agent %s: %v", agentID, err)
+			continue
+		}
+		if event.Type == MemoryUpdated || event.Type == MemoryDeleted {
+			c.invalidateMemory(event.Memory.ID, agentID)
+		}
+
+		select {
+		case events <- event:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// dialSubscription opens the WebSocket for an agent's events, with the same
+// headers, authorization and circuit breaker as any other request
+func (c *Client) dialSubscription(ctx context.Context, agentID string) (*websocket.Conn, error) {
+	if err := c.waitForRateLimit(ctx); err != nil {
+		return nil, err
+	}
+
+	endpoint, err := url.Parse(c.config.GetAPIBaseURL() + "/memories/subscribe")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	switch endpoint.Scheme {
+	case "https":
+		endpoint.Scheme = "wss"
+	default:
+		endpoint.Scheme = "ws"
+	}
+	endpoint.RawQuery = url.Values{"agent_id": {agentID}}.Encode()
+
+	// Build the handshake headers on a request so that authorization and
+	// request hooks apply as they do elsewhere
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, value := range c.config.GetDefaultHeaders() {
+		req.Header.Set(key, value)
+	}
+	req.Header.Del("Accept-Encoding")
+	req.Header.Del("Content-Type")
+	var requestID string
+	if c.config.RequestIDHeader != "" {
+		requestID = c.requestID(ctx)
+		req.Header.Set(c.config.RequestIDHeader, requestID)
+	}
+	if err := c.authorize(req); err != nil {
+		return nil, withRequestID(err, requestID)
+	}
+	c.runRequestHooks(req)
+
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+
+	conn, resp, err := c.subscriptionDialer().DialContext(ctx, req.URL.String(), req.Header)
+	if err != nil {
+		if resp != nil && errors.Is(err, websocket.ErrBadHandshake) {
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			err = handleHTTPError(resp.StatusCode, errorMessage(resp.StatusCode, resp.Status, body))
+			err = withRequestID(err, c.responseRequestID(resp.Header))
+		} else {
+			err = requestError(ctx, err)
+		}
+		err = withRequestID(err, requestID)
+		c.breaker.record(err)
+		return nil, err
+	}
+
+	c.breaker.record(nil)
+	return conn, nil
+}
+
+// subscriptionDialer returns the WebSocket dialer for subscriptions,
+// carrying over the proxy, TLS and dial settings of a custom HTTPClient
+func (c *Client) subscriptionDialer() *websocket.Dialer {
+	dialer := &websocket.Dialer{
+		Proxy:            c.proxy(),
+		NetDialContext:   c.netDialer().DialContext,
+		HandshakeTimeout: c.config.Timeout,
+	}
+	if c.config.HTTPClient != nil {
+		if transport, ok := c.config.HTTPClient.Transport.(*http.Transport); ok {
+			dialer.Proxy = transport.Proxy
+			dialer.TLSClientConfig = transport.TLSClientConfig
+			dialer.NetDialContext = transport.DialContext
+		}
+	}
+	return dialer
+}
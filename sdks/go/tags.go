@@ -0,0 +1,48 @@
+package agentmem
+
+import (
+	"context"
+	"fmt"
+)
+
+// AddTags tags a memory. Tags it already has are left as they are.
+func (c *Client) AddTags(ctx context.Context, memoryID string, tags []string) error {
+	return c.changeTags(ctx, "POST", memoryID, tags)
+}
+
+// RemoveTags removes tags from a memory. Tags it does not have are ignored.
+func (c *Client) RemoveTags(ctx context.Context, memoryID string, tags []string) error {
+	return c.changeTags(ctx, "DELETE", memoryID, tags)
+}
+
+// changeTags adds or removes the tags of a memory
+func (c *Client) changeTags(ctx context.Context, method, memoryID string, tags []string) error {
+	if memoryID == "" {
+		return NewValidationError("memory ID is required")
+	}
+	if len(tags) == 0 {
+		return NewValidationError("at least one tag is required")
+	}
+	if err := validateTags(tags); err != nil {
+		return err
+	}
+
+	params := TagsParams{Tags: tags}
+	err := c.makeRequest(ctx, method, fmt.Sprintf("/memories/%s/tags", memoryID), params, nil, false)
+	if err != nil {
+		return err
+	}
+	c.invalidateMemory(memoryID, "")
+	return nil
+}
+
+// SearchByTags finds an agent's memories tagged with any or all of tags,
+// depending on mode
+func (c *Client) SearchByTags(ctx context.Context, agentID string, tags []string, mode TagMatchMode, limit int) ([]SearchResult, error) {
+	return c.SearchMemories(ctx, SearchQuery{
+		AgentID:      agentID,
+		Tags:         tags,
+		TagMatchMode: mode,
+		Limit:        limit,
+	})
+}
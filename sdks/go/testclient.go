@@ -0,0 +1,58 @@
+package agentmem
+
+import (
+	"net/http"
+	"net/http/httptest"
+)
+
+// NewTestClient creates a client that sends every request to handler in
+// process, without opening a network connection, for unit tests of code
+// that uses the SDK. Pair it with a MemoryServer for realistic behavior:
+//
+//	client := agentmem.NewTestClient(agentmem.NewMemoryServer())
+//
+// Retries and caching are disabled so that each call reaches the handler
+// exactly once. For other settings, use NewTestHTTPClient with a Config.
+func NewTestClient(handler http.Handler) *Client {
+	config := NewConfig("test-api-key").WithHTTPClient(NewTestHTTPClient(handler))
+	config.MaxRetries = 0
+	config.EnableCaching = false
+	client, err := NewClient(config)
+	if err != nil {
+		// The configuration above is always valid
+		panic(err)
+	}
+	return client
+}
+
+// NewTestHTTPClient returns an HTTP client that serves every request with
+// handler in process. Set it with Config.WithHTTPClient to test a client
+// with a specific configuration.
+func NewTestHTTPClient(handler http.Handler) *http.Client {
+	return &http.Client{Transport: handlerTransport{handler: handler}}
+}
+
+// handlerTransport is a RoundTripper that calls an http.Handler directly
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		defer req.Body.Close()
+	}
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+
+	// Handlers expect a server request, whose body is never nil
+	serverReq := req.Clone(req.Context())
+	if serverReq.Body == nil {
+		serverReq.Body = http.NoBody
+	}
+	recorder := httptest.NewRecorder()
+	t.handler.ServeHTTP(recorder, serverReq)
+	resp := recorder.Result()
+	resp.Request = req
+	return resp, nil
+}
@@ -0,0 +1,136 @@
+package agentmem
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const tracerName = "github.com/agentmem/agentmem-go"
+
+// operationNames maps "METHOD route" to the client method issuing the request
+var operationNames = map[string]string{
+	"POST /memories":              "AddMemory",
+	"GET /memories":               "ListMemories",
+	"GET /memories/{id}":          "GetMemory",
+	"PUT /memories/{id}":          "UpdateMemory",
+	"DELETE /memories/{id}":       "DeleteMemory",
+	"POST /memories/search":       "SearchMemories",
+	"POST /memories/batch":        "BatchAddMemories",
+	"POST /memories/batch/get":    "GetMemories",
+	"POST /memories/batch/delete": "BatchDeleteMemories",
+	"POST /memories/batch/update": "BatchUpdateMemories",
+	"GET /memories/stats":         "GetMemoryStats",
+	"GET /memories/count":         "CountMemories",
+	"GET /memories/embeddings":    "GetEmbeddings",
+	"POST /memories/decay":        "ApplyDecay",
+	"POST /memories/delete":       "DeleteMemoriesByFilter",
+	"POST /memories/{id}/links":   "LinkMemories",
+	"POST /memories/{id}/tags":    "AddTags",
+	"DELETE /memories/{id}/tags":  "RemoveTags",
+	"GET /memories/{id}/related":  "GetRelatedMemories",
+	"GET /memories/{id}/history":  "GetMemoryHistory",
+	"POST /batch":                 "ExecuteBatch",
+	"GET /health":                 "HealthCheck",
+	"GET /metrics":                "GetMetrics",
+}
+
+// newTracer returns the tracer for a client, falling back to a no-op
+// tracer when no provider is configured
+func newTracer(provider trace.TracerProvider) trace.Tracer {
+	if provider == nil {
+		provider = noop.NewTracerProvider()
+	}
+	return provider.Tracer(tracerName)
+}
+
+// startSpan starts the span covering one API call, including its retries
+func (c *Client) startSpan(ctx context.Context, method, endpoint string, body interface{}) (context.Context, trace.Span) {
+	route := endpointRoute(endpoint)
+	ctx, span := c.tracer.Start(ctx, "AgentMem "+method+" "+route, trace.WithSpanKind(trace.SpanKindClient))
+	if !span.IsRecording() {
+		return ctx, span
+	}
+
+	span.SetAttributes(
+		attribute.String("memory.operation", operationName(method, route)),
+		attribute.String("http.method", method),
+		attribute.String("http.route", route),
+	)
+	if agentID := requestAgentID(body); agentID != "" {
+		span.SetAttributes(attribute.String("agent_id", agentID))
+	}
+	return ctx, span
+}
+
+// operationName returns the client method issuing a request to route, or
+// "METHOD route" for routes without a known method
+func operationName(method, route string) string {
+	if operation, ok := operationNames[method+" "+route]; ok {
+		return operation
+	}
+	return method + " " + route
+}
+
+// endSpan records the outcome of an API call and ends its span
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// recordResponse adds the outcome of the HTTP round trip to a span
+func recordResponse(span trace.Span, resp *resty.Response) {
+	if resp == nil || resp.RawResponse == nil || !span.IsRecording() {
+		return
+	}
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode()))
+	if resp.Request != nil && resp.Request.Attempt > 1 {
+		span.SetAttributes(attribute.Int("http.retry_count", resp.Request.Attempt-1))
+	}
+}
+
+// injectTraceContext propagates the span context to the server through the
+// request headers, using the globally configured propagator
+func injectTraceContext(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// endpointRoute replaces the memory ID in an endpoint with a placeholder so
+// that span names have a bounded cardinality
+func endpointRoute(endpoint string) string {
+	segments := strings.Split(strings.TrimPrefix(endpoint, "/"), "/")
+	if len(segments) >= 2 && segments[0] == "memories" {
+		switch segments[1] {
+		case "search", "batch", "stats", "count", "decay", "delete", "embeddings":
+		default:
+			segments[1] = "{id}"
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// requestAgentID extracts the agent ID a request is scoped to, if any
+func requestAgentID(body interface{}) string {
+	switch params := body.(type) {
+	case CreateMemoryParams:
+		return params.AgentID
+	case *CreateMemoryParams:
+		return params.AgentID
+	case SearchQuery:
+		return params.AgentID
+	case *SearchQuery:
+		return params.AgentID
+	}
+	return cacheAgentID(body, nil)
+}
@@ -3,7 +3,11 @@
 package agentmem
 
 import (
+	"net/http"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // MemoryType represents the type of memory
@@ -38,6 +42,80 @@ const (
 	MatchTypePartialText MatchType = "partial_text"
 	MatchTypeSemantic    MatchType = "semantic"
 	MatchTypeMetadata    MatchType = "metadata"
+	MatchTypeHybrid      MatchType = "hybrid"
+)
+
+// FilterOperator represents a metadata filter comparison operator
+type FilterOperator string
+
+const (
+	FilterOpEq       FilterOperator = "eq"
+	FilterOpNe       FilterOperator = "ne"
+	FilterOpIn       FilterOperator = "in"
+	FilterOpGt       FilterOperator = "gt"
+	FilterOpGte      FilterOperator = "gte"
+	FilterOpLt       FilterOperator = "lt"
+	FilterOpLte      FilterOperator = "lte"
+	FilterOpContains FilterOperator = "contains"
+	FilterOpExists   FilterOperator = "exists"
+)
+
+// ClearableField names a memory field that an update can reset
+type ClearableField string
+
+const (
+	// ClearImportance resets importance to the server default
+	ClearImportance ClearableField = "importance"
+	// ClearMetadata removes all metadata from the memory
+	ClearMetadata ClearableField = "metadata"
+)
+
+// MetadataMergeMode controls how an update's metadata is applied
+type MetadataMergeMode string
+
+const (
+	// MetadataReplace replaces the memory's metadata with the given map
+	MetadataReplace MetadataMergeMode = "replace"
+	// MetadataMerge adds or updates only the given keys, preserving the
+	// others; a key set to nil is deleted
+	MetadataMerge MetadataMergeMode = "merge"
+)
+
+// TagMatchMode controls how a search matches the tags it lists
+type TagMatchMode string
+
+const (
+	// TagMatchAny matches memories with at least one of the tags
+	TagMatchAny TagMatchMode = "any"
+	// TagMatchAll matches memories with every one of the tags
+	TagMatchAll TagMatchMode = "all"
+)
+
+// SortField names the memory field results are ordered by
+type SortField string
+
+const (
+	// SortByRelevance orders search results by score; it only applies to
+	// searches with a text or vector query
+	SortByRelevance SortField = "relevance"
+	// SortByImportance orders memories by importance
+	SortByImportance SortField = "importance"
+	// SortByCreatedAt orders memories by creation time
+	SortByCreatedAt SortField = "created_at"
+	// SortByUpdatedAt orders memories by last update time
+	SortByUpdatedAt SortField = "updated_at"
+	// SortByAccessCount orders memories by how often they were accessed
+	SortByAccessCount SortField = "access_count"
+)
+
+// SortOrder is the direction results are sorted in
+type SortOrder string
+
+const (
+	// SortAsc sorts lowest or oldest first
+	SortAsc SortOrder = "asc"
+	// SortDesc sorts highest or newest first
+	SortDesc SortOrder = "desc"
 )
 
 // Memory represents a memory record
@@ -50,24 +128,89 @@ type Memory struct {
 	SessionID    *string                `json:"session_id,omitempty"`
 	Importance   float64                `json:"importance"`
 	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+	Tags         []string               `json:"tags,omitempty"`
 	CreatedAt    *time.Time             `json:"created_at,omitempty"`
 	UpdatedAt    *time.Time             `json:"updated_at,omitempty"`
 	AccessCount  int                    `json:"access_count"`
 	LastAccessed *time.Time             `json:"last_accessed,omitempty"`
 	Embedding    []float64              `json:"embedding,omitempty"`
+	// Relations lists the memory's links; it is only populated when
+	// requested, e.g. by GetMemoryWithRelations
+	Relations []MemoryRelation `json:"relations,omitempty"`
+}
+
+// RelationDirection tells whether a link points from or to a memory
+type RelationDirection string
+
+const (
+	// RelationOutgoing is a link from the memory to another one
+	RelationOutgoing RelationDirection = "outgoing"
+	// RelationIncoming is a link from another memory to the memory
+	RelationIncoming RelationDirection = "incoming"
+)
+
+// MemoryRelation represents a link between a memory and another one
+type MemoryRelation struct {
+	MemoryID     string            `json:"memory_id"`
+	RelationType string            `json:"relation_type"`
+	Direction    RelationDirection `json:"direction"`
+}
+
+// RelatedMemory represents a memory linked to another one
+type RelatedMemory struct {
+	Memory       Memory            `json:"memory"`
+	RelationType string            `json:"relation_type"`
+	Direction    RelationDirection `json:"direction"`
 }
 
 // SearchQuery represents search parameters
 type SearchQuery struct {
-	AgentID         string                 `json:"agent_id"`
+	AgentID string `json:"agent_id,omitempty"`
+	// AgentIDs searches the memories of several agents at once, instead of
+	// those of AgentID
+	AgentIDs        []string               `json:"agent_ids,omitempty"`
 	TextQuery       *string                `json:"text_query,omitempty"`
 	VectorQuery     []float64              `json:"vector_query,omitempty"`
+	EmbedText       string                 `json:"-"`
+	HybridWeight    *float64               `json:"hybrid_weight,omitempty"`
 	MemoryType      *MemoryType            `json:"memory_type,omitempty"`
 	UserID          *string                `json:"user_id,omitempty"`
 	MinImportance   *float64               `json:"min_importance,omitempty"`
 	MaxAgeSeconds   *int                   `json:"max_age_seconds,omitempty"`
+	CreatedAfter    *time.Time             `json:"created_after,omitempty"`
+	CreatedBefore   *time.Time             `json:"created_before,omitempty"`
+	UpdatedAfter    *time.Time             `json:"updated_after,omitempty"`
+	UpdatedBefore   *time.Time             `json:"updated_before,omitempty"`
 	Limit           int                    `json:"limit"`
 	MetadataFilters map[string]interface{} `json:"metadata_filters,omitempty"`
+	AdvancedFilters []MetadataFilter       `json:"advanced_filters,omitempty"`
+	Tags            []string               `json:"tags,omitempty"`
+	// TagMatchMode controls how Tags are matched (default: TagMatchAny)
+	TagMatchMode TagMatchMode `json:"tag_match_mode,omitempty"`
+	// SortBy and SortOrder order the results regardless of their scores
+	// (default: SortByRelevance, SortDesc)
+	SortBy       SortField `json:"sort_by,omitempty"`
+	SortOrder    SortOrder `json:"sort_order,omitempty"`
+	IncludeTotal bool      `json:"include_total,omitempty"`
+	// Cursor continues a previous search from its NextCursor
+	Cursor string `json:"cursor,omitempty"`
+	// SnapshotID pages through the result set of the search that returned
+	// it, so memories written in between do not shift pages and cause
+	// duplicates or skips. Searches that fetch all pages set it themselves.
+	SnapshotID *string `json:"snapshot_id,omitempty"`
+	// IncludeEmbeddings returns the embedding vector of each result; it is
+	// sent even when false, so the server omits them (default: false)
+	IncludeEmbeddings bool `json:"include_embeddings"`
+	// Highlight fills the Highlights of each result, from the server when it
+	// provides them and otherwise from matches of TextQuery in the content
+	Highlight bool `json:"highlight,omitempty"`
+}
+
+// MetadataFilter represents a metadata condition using a comparison operator
+type MetadataFilter struct {
+	Field    string         `json:"field"`
+	Operator FilterOperator `json:"operator"`
+	Value    interface{}    `json:"value,omitempty"`
 }
 
 // SearchResult represents a search result with score and match type
@@ -75,6 +218,23 @@ type SearchResult struct {
 	Memory    Memory    `json:"memory"`
 	Score     float64   `json:"score"`
 	MatchType MatchType `json:"match_type"`
+	// TextScore and VectorScore are the components fused into Score by a
+	// hybrid search, when the server reports them
+	TextScore   *float64 `json:"text_score,omitempty"`
+	VectorScore *float64 `json:"vector_score,omitempty"`
+	// Highlights are the fragments of the content matching the text query,
+	// when the query sets Highlight
+	Highlights []string `json:"highlights,omitempty"`
+}
+
+// StatsQuery represents the subset of an agent's memories to compute
+// statistics over. Unset filters do not restrict the subset.
+type StatsQuery struct {
+	AgentID       string
+	MemoryType    *MemoryType
+	MinImportance *float64
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
 }
 
 // MemoryStats represents memory statistics
@@ -97,6 +257,13 @@ type CreateMemoryParams struct {
 	SessionID  *string                `json:"session_id,omitempty"`
 	Importance *float64               `json:"importance,omitempty"`
 	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+	Tags       []string               `json:"tags,omitempty"`
+	// ImportanceLevel sets Importance to the level's ToFloat value when
+	// Importance is nil; setting both is an error
+	ImportanceLevel ImportanceLevel `json:"-"`
+	// IdempotencyKey is sent in the Idempotency-Key header so that repeated
+	// attempts create the memory only once
+	IdempotencyKey string `json:"-"`
 }
 
 // UpdateMemoryParams represents parameters for updating a memory
@@ -104,11 +271,98 @@ type UpdateMemoryParams struct {
 	Content    *string                `json:"content,omitempty"`
 	Importance *float64               `json:"importance,omitempty"`
 	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+	MemoryType *MemoryType            `json:"memory_type,omitempty"`
+	UserID     *string                `json:"user_id,omitempty"`
+	// ClearFields lists fields to reset. Unset fields are left unchanged,
+	// so clearing is the only way to remove a previously set value.
+	ClearFields []ClearableField `json:"clear_fields,omitempty"`
+	// MetadataMergeMode controls how Metadata is applied (default:
+	// MetadataReplace)
+	MetadataMergeMode MetadataMergeMode `json:"metadata_merge_mode,omitempty"`
 }
 
 // BatchCreateMemoryParams represents parameters for batch memory creation
 type BatchCreateMemoryParams struct {
 	Memories []CreateMemoryParams `json:"memories"`
+	// DryRun validates the memories without creating them
+	DryRun bool `json:"-"`
+	// IdempotencyKey is sent in the Idempotency-Key header so that repeated
+	// attempts create the batch only once
+	IdempotencyKey string `json:"-"`
+}
+
+// Projection selects which optional fields are returned for a memory
+type Projection struct {
+	// ExcludeEmbedding omits the embedding vector from returned memories
+	ExcludeEmbedding bool
+}
+
+// BatchGetMemoryParams represents parameters for fetching memories by ID
+type BatchGetMemoryParams struct {
+	IDs              []string `json:"ids"`
+	ExcludeEmbedding bool     `json:"exclude_embedding,omitempty"`
+}
+
+// BatchOpType represents the kind of operation in a mixed batch
+type BatchOpType string
+
+const (
+	BatchOpCreate BatchOpType = "create"
+	BatchOpUpdate BatchOpType = "update"
+	BatchOpDelete BatchOpType = "delete"
+	BatchOpLink   BatchOpType = "link"
+)
+
+// BatchOp represents one operation in a mixed batch. Only the payload
+// matching Type is set; use the NewXxxOp constructors to build one.
+type BatchOp struct {
+	Type     BatchOpType         `json:"type"`
+	MemoryID string              `json:"memory_id,omitempty"`
+	Create   *CreateMemoryParams `json:"create,omitempty"`
+	Update   *UpdateMemoryParams `json:"update,omitempty"`
+	Link     *LinkParams         `json:"link,omitempty"`
+}
+
+// LinkParams represents the target and relation of a memory link
+type LinkParams struct {
+	TargetID     string `json:"target_id"`
+	RelationType string `json:"relation_type"`
+}
+
+// TagsParams represents the tags added to or removed from a memory
+type TagsParams struct {
+	Tags []string `json:"tags"`
+}
+
+// BatchRequest represents a mixed batch of operations
+type BatchRequest struct {
+	Operations []BatchOp `json:"operations"`
+	// Atomic asks the server to apply all operations or none
+	Atomic bool `json:"atomic,omitempty"`
+}
+
+// BatchOpResult represents the outcome of one operation in a mixed batch
+type BatchOpResult struct {
+	Success bool `json:"success"`
+	// MemoryID is the created or affected memory
+	MemoryID string  `json:"memory_id,omitempty"`
+	Error    *string `json:"error,omitempty"`
+}
+
+// ImportOptions represents options for bulk-importing memories
+type ImportOptions struct {
+	// BatchSize is the number of records per BatchAddMemories call (default: 100)
+	BatchSize int
+	// Concurrency is the number of batches submitted in parallel (default: 1)
+	Concurrency int
+}
+
+// ImportResult represents the outcome of a bulk import
+type ImportResult struct {
+	Created int
+	Failed  int
+	// Errors describes each failed record, in input order
+	Errors []*ImportRecordError
 }
 
 // HealthStatus represents API health status
@@ -130,48 +384,325 @@ type SystemMetrics struct {
 	CacheHitRate          float64 `json:"cache_hit_rate"`
 }
 
+// ClientStats represents client-side request statistics
+type ClientStats struct {
+	// Requests is the number of HTTP attempts that received a response, including retries
+	Requests int64
+	// Retries is the total number of retried attempts
+	Retries int64
+	// RetriesByEndpoint breaks Retries down by "METHOD route", where the
+	// route is the endpoint with memory IDs replaced by "{id}", such as
+	// "GET /memories/{id}"
+	RetriesByEndpoint map[string]int64
+	// BytesSent is the total size of request bodies sent
+	BytesSent int64
+	// BytesReceived is the total size of response bodies received
+	BytesReceived int64
+	// TotalLatency is the cumulative time spent waiting for responses
+	TotalLatency time.Duration
+	// AverageLatency is TotalLatency divided by Requests
+	AverageLatency time.Duration
+}
+
+// CacheStats represents statistics about the client's local response cache
+type CacheStats struct {
+	// Hits is the number of lookups served from the cache
+	Hits int64
+	// Misses is the number of lookups not found in the cache or expired
+	Misses int64
+	// Evictions is the number of entries dropped to make room for new ones
+	Evictions int64
+	// Entries is the number of entries currently cached
+	Entries int
+	// HitRate is Hits divided by the total number of lookups
+	HitRate float64
+}
+
+// BackoffStrategy represents how the wait between retries grows
+type BackoffStrategy string
+
+const (
+	// BackoffDefault doubles the wait after each attempt, up to
+	// MaxRetryDelay, and waits a random duration between half and all of it
+	BackoffDefault BackoffStrategy = ""
+	// BackoffFixed waits RetryDelay before every retry
+	BackoffFixed BackoffStrategy = "fixed"
+	// BackoffExponential doubles the wait after each attempt, starting at
+	// RetryDelay and capped at MaxRetryDelay
+	BackoffExponential BackoffStrategy = "exponential"
+	// BackoffExponentialJitter waits a random duration between 0 and the
+	// exponential backoff, so that many clients retrying at once spread out
+	BackoffExponentialJitter BackoffStrategy = "exponential_jitter"
+)
+
 // Config represents client configuration
 type Config struct {
-	// APIKey for authentication (required)
+	// APIKey for authentication (required unless OAuth2 is set)
 	APIKey string
 	
+	// OAuth2 authenticates with client-credentials bearer tokens instead
+	// of the API key (default: nil, API key authentication)
+	OAuth2 *OAuth2Config
+	
 	// BaseURL for the AgentMem API (default: https://api.agentmem.dev)
 	BaseURL string
 	
 	// APIVersion (default: v1)
 	APIVersion string
 	
-	// Timeout for requests (default: 30s)
+	// Timeout for requests (default: 30s). For streaming requests it only
+	// bounds the handshake, up to the arrival of the response headers.
 	Timeout time.Duration
 	
+	// MaxSearchResults caps how many matches a search with Limit 0
+	// fetches across pages (default: 0, meaning 10000)
+	MaxSearchResults int
+	
+	// StreamTimeout bounds the total lifetime of streaming connections
+	// (default: 0, no timeout)
+	StreamTimeout time.Duration
+	
+	// HTTPClient is used for all requests when set, e.g. to supply a custom
+	// transport, proxy, or TLS configuration. The client is copied, so it is
+	// never modified by the SDK. (default: nil, a client created by the SDK)
+	HTTPClient *http.Client
+	
+	// MaxIdleConns bounds the idle keep-alive connections kept open, 0
+	// meaning no limit (default: 100). Like MaxIdleConnsPerHost and
+	// IdleConnTimeout, it does not apply to a custom HTTPClient.
+	MaxIdleConns int
+	
+	// MaxIdleConnsPerHost bounds the idle keep-alive connections kept open
+	// to the API host (default: 100)
+	MaxIdleConnsPerHost int
+	
+	// IdleConnTimeout is how long an idle keep-alive connection is kept
+	// open, 0 meaning no limit (default: 90s)
+	IdleConnTimeout time.Duration
+	
+	// DialTimeout, TLSHandshakeTimeout, and ResponseHeaderTimeout bound the
+	// phases of a request, each within the overall Timeout: opening a
+	// connection, the TLS handshake, and waiting for response headers once
+	// the request is sent. 0 means no limit beyond Timeout. They do not
+	// apply to a custom HTTPClient.
+	// (default: 30s, 10s, and 0, as for http.DefaultTransport)
+	DialTimeout           time.Duration
+	TLSHandshakeTimeout   time.Duration
+	ResponseHeaderTimeout time.Duration
+	
+	// MaxResponseBytes limits the size of a response body after
+	// decompression; larger responses fail with a ResponseTooLargeError.
+	// Streaming responses are not limited. (default: 0, no limit)
+	MaxResponseBytes int
+	
+	// ProxyURL routes all requests through the given HTTP(S) proxy, taking
+	// precedence over the HTTP_PROXY, HTTPS_PROXY, and NO_PROXY environment
+	// variables, which are honored when it is empty. A custom HTTPClient
+	// takes precedence over both: its transport is used as is.
+	// (default: "", proxy from the environment)
+	ProxyURL string
+	
 	// MaxRetries for failed requests (default: 3)
 	MaxRetries int
 	
 	// RetryDelay between retries (default: 1s)
 	RetryDelay time.Duration
 	
+	// MaxRetryDelay caps the backoff between retries (default: 0, meaning 10x RetryDelay)
+	MaxRetryDelay time.Duration
+	
+	// FullJitter waits a random duration between 0 and the capped exponential
+	// backoff, avoiding synchronized retries across many clients. It is
+	// equivalent to Backoff set to BackoffExponentialJitter. (default: false)
+	FullJitter bool
+	
+	// Backoff selects how the wait between retries grows
+	// (default: BackoffDefault, exponential with equal jitter)
+	Backoff BackoffStrategy
+	
 	// EnableCompression for requests/responses (default: true)
 	EnableCompression bool
 	
+	// CompressionThreshold is the size in bytes above which JSON request
+	// bodies are gzipped when EnableCompression is set (default: 1024)
+	CompressionThreshold int
+	
 	// EnableCaching for GET requests (default: true)
 	EnableCaching bool
 	
 	// CacheTTL for cached responses (default: 5m)
 	CacheTTL time.Duration
 	
+	// Cache replaces the built-in in-memory response cache, e.g. with a
+	// shared Redis-backed one. MaxCacheEntries and partitioning by agent only
+	// apply to the built-in cache. Its keys cannot be enumerated for eviction,
+	// so counts and filtered stats are not cached in it. (default: nil, the
+	// built-in cache)
+	Cache Cache
+	
+	// MaxCacheEntries bounds the response cache; the least-recently-used
+	// entry is evicted when it is full, and 0 means unbounded (default: 1000)
+	MaxCacheEntries int
+	
+	// CachePartitionByAgent keeps a separate LRU cache segment per agent so
+	// one agent's reads cannot evict another agent's entries (default: false)
+	CachePartitionByAgent bool
+	
+	// CacheMaxEntriesPerAgent bounds each agent's cache segment when
+	// CachePartitionByAgent is enabled (default: 100)
+	CacheMaxEntriesPerAgent int
+	
+	// ConditionalRequests makes GetMemory remember the ETag of each memory
+	// it reads, up to MaxCacheEntries, and revalidate it with If-None-Match
+	// once its cache entry has expired; a 304 Not Modified reuses the stored
+	// memory. Enable it only for servers that send ETags (default: false)
+	ConditionalRequests bool
+	
 	// EnableLogging for debug output (default: false)
 	EnableLogging bool
 	
+	// Logger receives log output when EnableLogging is set
+	// (default: nil, the standard library's default logger)
+	Logger Logger
+	
+	// TracerProvider creates the spans wrapping each API call
+	// (default: nil, tracing disabled)
+	TracerProvider trace.TracerProvider
+	
+	// PrometheusRegisterer receives the client's request, retry, and cache
+	// metrics (default: nil, no metrics are exported)
+	PrometheusRegisterer prometheus.Registerer
+	
+	// Embedder fills the vector query of searches that set EmbedText
+	// (default: nil, EmbedText is rejected)
+	Embedder Embedder
+	
+	// Reranker reorders the results of SearchMemoriesReranked
+	// (default: nil, reranked searches are rejected)
+	Reranker Reranker
+	
+	// OfflineStore queues memories that AddMemory cannot send because of a
+	// network failure, to be sent later by FlushOfflineQueue
+	// (default: nil, network failures are returned)
+	OfflineStore OfflineStore
+	
+	// OnOfflineFlush is called for each queued memory FlushOfflineQueue
+	// handles, with its provisional and server IDs, or with the error if
+	// the server rejected it and it was dropped from the queue
+	OnOfflineFlush func(localID, serverID string, err error)
+	
 	// CustomHeaders to include in requests
 	CustomHeaders map[string]string
+	
+	// RequestHooks run in order on every outgoing HTTP request, including
+	// each retry attempt, right before it is sent: after the default, custom,
+	// per-request, request ID, and trace headers have been set
+	RequestHooks []func(*http.Request)
+	
+	// ResponseHooks run in order once per API call, after retries are
+	// exhausted and before the error is returned or the body is decoded and
+	// cached. The error is the SDK's typed error for the final attempt, if
+	// any, and the response is nil when no response was received. They do
+	// not run for cache hits or streaming requests.
+	ResponseHooks []func(*http.Response, error)
+	
+	// RateLimit caps the requests per second sent by the client; requests
+	// wait for their turn instead of being rejected (default: 0, unlimited)
+	RateLimit float64
+	
+	// RateLimitBurst is the number of requests allowed at once above RateLimit
+	// (default: 1)
+	RateLimitBurst int
+	
+	// CircuitBreakerThreshold is the number of consecutive server or network
+	// failures after which requests fail fast with a CircuitOpenError
+	// (default: 0, disabled)
+	CircuitBreakerThreshold int
+	
+	// CircuitBreakerCooldown is how long the circuit stays open before a
+	// single trial request is let through (default: 30s)
+	CircuitBreakerCooldown time.Duration
+	
+	// RetryBudget caps retries across the client at this fraction of its
+	// requests, e.g. 0.1 for one retry per ten requests, so that an outage
+	// does not multiply the load; once spent, failed requests are not
+	// retried (default: 0, retries are limited only by MaxRetries)
+	RetryBudget float64
+	
+	// GateOnHealth fails requests fast with a ServerError while the most
+	// recent HealthCheck, within HealthGateTTL, reported the backend as
+	// unhealthy. HealthCheck and Critical requests are never gated (default: false)
+	GateOnHealth bool
+	
+	// HealthGateTTL is how long a health check result gates requests (default: 10s)
+	HealthGateTTL time.Duration
+	
+	// RequestIDHeader is the header carrying each request's correlation ID;
+	// empty disables it (default: X-Request-ID)
+	RequestIDHeader string
+	
+	// RequestIDGenerator generates correlation IDs for requests whose context
+	// has none set with WithRequestID (default: random UUIDv4)
+	RequestIDGenerator func() string
+	
+	// AutoIdempotencyKeys sends a random Idempotency-Key with every
+	// AddMemory and BatchAddMemories call that has no IdempotencyKey set,
+	// so the server can deduplicate retried creates (default: false)
+	AutoIdempotencyKeys bool
+	
+	// ImportanceScaler normalizes caller-supplied importance to 0..1 before
+	// memories are created, e.g. ScaleImportance(0, 100) (default: nil)
+	ImportanceScaler func(float64) float64
+	
+	// ImportanceScorer assigns an importance to memories created without
+	// one, by AddMemory, BatchAddMemories, ImportMemories, or ExecuteBatch
+	// (default: nil, the server default applies)
+	ImportanceScorer ImportanceScorer
+	
+	// DefaultImportanceByType assigns an importance by memory type to
+	// memories created without one, when no ImportanceScorer is set
+	// (default: nil, the server default applies)
+	DefaultImportanceByType map[MemoryType]float64
+	
+	// JSONMarshal and JSONUnmarshal replace encoding/json for request and
+	// response bodies and cache copies, e.g. with a faster compatible
+	// library (default: nil, encoding/json)
+	JSONMarshal   func(v interface{}) ([]byte, error)
+	JSONUnmarshal func(data []byte, v interface{}) error
+	
+	// StrictMemoryTypes fails decoding a response that holds a memory type
+	// the SDK does not know, e.g. one added by a newer server, instead of
+	// decoding it as MemoryTypeUntyped (default: false)
+	StrictMemoryTypes bool
 }
 
 // RequestOptions represents options for individual requests
 type RequestOptions struct {
+	// Timeout overrides the configured Timeout for each attempt
 	Timeout    *time.Duration
+	// Retries overrides the configured MaxRetries
 	Retries    *int
+	// UseCache overrides whether the response is served from and stored in the cache
 	UseCache   *bool
+	// Headers are added to the request, overriding default headers
 	Headers    map[string]string
+	// CacheTTL overrides the configured CacheTTL for the cached response
+	CacheTTL   *time.Duration
+	// Critical exempts the request from the GateOnHealth fail-fast check
+	Critical   bool
+	// RecordAccess bypasses the cache for the read, so the server tracks the
+	// access; the response is still cached unless UseCache is false
+	RecordAccess bool
+	// IncludeEmbedding returns the embedding vector of a memory read with
+	// GetMemoryWithOptions, which otherwise asks the server to omit it
+	IncludeEmbedding bool
+}
+
+// RawResponse represents an undecoded API response returned by DoRaw
+type RawResponse struct {
+	StatusCode int
+	Headers    http.Header
+	Body       []byte
 }
 
 // APIResponse represents a generic API response
@@ -185,6 +716,41 @@ type APIResponse struct {
 // SearchResponse represents search API response
 type SearchResponse struct {
 	Results []SearchResult `json:"results"`
+	// Total is the number of matches across all pages; only set when the
+	// query had IncludeTotal, since computing it can be expensive
+	Total *int `json:"total,omitempty"`
+	// NextCursor fetches the next page; empty when there are no more results
+	NextCursor string `json:"next_cursor,omitempty"`
+	// SnapshotID identifies the result set being paged, for use as the
+	// SnapshotID of the following pages; empty if the server does not
+	// support snapshots, in which case pages may shift under writes
+	SnapshotID string `json:"snapshot_id,omitempty"`
+}
+
+// ListMemoriesParams represents parameters for listing an agent's memories
+type ListMemoriesParams struct {
+	AgentID  string
+	PageSize int
+	// Cursor is the NextCursor of the previous page; empty for the first page
+	Cursor string
+	// MemoryType and MinImportance, when set, restrict the listed memories
+	MemoryType    *MemoryType
+	MinImportance *float64
+	// SortBy orders the memories, highest or newest first (default: server order)
+	SortBy SortField
+	// SnapshotID is the SnapshotID of the previous page, keeping the listed
+	// set stable under concurrent writes; empty for the first page
+	SnapshotID string
+}
+
+// ListMemoriesResult represents one page of an agent's memories
+type ListMemoriesResult struct {
+	Memories []Memory `json:"memories"`
+	// NextCursor fetches the next page; empty when iteration is complete
+	NextCursor string `json:"next_cursor,omitempty"`
+	// SnapshotID identifies the listed set, for use in the following pages'
+	// params; empty if the server does not support snapshots
+	SnapshotID string `json:"snapshot_id,omitempty"`
 }
 
 // BatchCreateResponse represents batch create API response
@@ -192,6 +758,118 @@ type BatchCreateResponse struct {
 	IDs []string `json:"ids"`
 }
 
+// BatchGetResponse represents batch get API response
+type BatchGetResponse struct {
+	Memories []Memory `json:"memories"`
+}
+
+// BatchResponse represents mixed batch API response
+type BatchResponse struct {
+	Results []BatchOpResult `json:"results"`
+}
+
+// BatchItemError represents the failure of one item in a batch operation
+type BatchItemError struct {
+	Error      string `json:"error"`
+	StatusCode int    `json:"status"`
+}
+
+// MemoryVersion represents one past or current version of a memory
+type MemoryVersion struct {
+	Version    int                    `json:"version"`
+	Content    string                 `json:"content"`
+	Importance float64                `json:"importance"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+	// Timestamp is when this version was written
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// MemoryHistoryResponse represents memory history API response
+type MemoryHistoryResponse struct {
+	Versions []MemoryVersion `json:"versions"`
+}
+
+// RelatedMemoriesResponse represents related memories API response
+type RelatedMemoriesResponse struct {
+	Related []RelatedMemory `json:"related"`
+}
+
+// DecayParams represents parameters for decaying memory importance
+type DecayParams struct {
+	AgentID string
+	// HalfLife is the time without access after which importance halves
+	HalfLife time.Duration
+	// MinImportance is a floor importance never decays below (optional)
+	MinImportance *float64
+	// DeletionThreshold deletes memories whose importance decays below it
+	// (optional; without it no memories are deleted)
+	DeletionThreshold *float64
+}
+
+// DecayResult represents the outcome of an importance decay
+type DecayResult struct {
+	// Adjusted is the number of memories whose importance changed
+	Adjusted int `json:"adjusted"`
+	// BelowThreshold is the number of memories that decayed below the
+	// deletion threshold
+	BelowThreshold int `json:"below_threshold"`
+}
+
+// CountResponse represents memory count API response
+type CountResponse struct {
+	Count int `json:"count"`
+}
+
+// DeleteByFilterResponse represents delete by filter API response
+type DeleteByFilterResponse struct {
+	Deleted int `json:"deleted"`
+}
+
+// BatchDeleteResponse represents batch delete API response
+type BatchDeleteResponse struct {
+	Deleted []string                  `json:"deleted"`
+	Failed  map[string]BatchItemError `json:"failed,omitempty"`
+}
+
+// BatchValidationResult represents the outcome of validating a batch create
+type BatchValidationResult struct {
+	// Failed maps the index of each invalid memory to its validation error
+	Failed map[int]error
+}
+
+// Valid reports whether every memory in the batch passed validation
+func (r BatchValidationResult) Valid() bool {
+	return len(r.Failed) == 0
+}
+
+// BatchDeleteResult represents the outcome of a batch delete
+type BatchDeleteResult struct {
+	// Deleted lists the IDs that were deleted
+	Deleted []string
+	// Failed maps each ID that could not be deleted to its error
+	Failed map[string]error
+}
+
+// BatchUpdateItem pairs a memory ID with the update to apply to it
+type BatchUpdateItem struct {
+	ID     string             `json:"id"`
+	Params UpdateMemoryParams `json:"params"`
+}
+
+// BatchUpdateResponse represents batch update API response
+type BatchUpdateResponse struct {
+	Updated []string                  `json:"updated"`
+	Failed  map[string]BatchItemError `json:"failed,omitempty"`
+}
+
+// BatchUpdateResult represents the outcome of a batch update
+type BatchUpdateResult struct {
+	// Updated lists the IDs that were updated
+	Updated []string
+	// Failed maps each ID that could not be updated to its error
+	Failed map[string]error
+}
+
 // CreateMemoryResponse represents create memory API response
 type CreateMemoryResponse struct {
 	ID string `json:"id"`
@@ -0,0 +1,233 @@
+package agentmem
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Validate checks the params before they are sent: content and agent ID are
+// required, the memory type, when set, must be known, importance, when set,
+// must be within [0, 1], and tags must not be blank
+func (p CreateMemoryParams) Validate() error {
+	if p.Content == "" {
+		return NewValidationError("content is required")
+	}
+	if p.AgentID == "" {
+		return NewValidationError("agent ID is required")
+	}
+	if err := validateMemoryType(p.MemoryType); err != nil {
+		return err
+	}
+	if p.Importance != nil {
+		if err := validateImportance("importance", *p.Importance); err != nil {
+			return err
+		}
+	}
+	if p.ImportanceLevel != 0 {
+		if !p.ImportanceLevel.IsValid() {
+			return NewValidationError(fmt.Sprintf("invalid importance level %d", int(p.ImportanceLevel)))
+		}
+		if p.Importance != nil {
+			return NewValidationError("importance and importance level cannot both be set")
+		}
+	}
+	return validateTags(p.Tags)
+}
+
+// Validate checks the query before it is sent: exactly one of AgentID and
+// AgentIDs is required, as is at least one of TextQuery, VectorQuery,
+// EmbedText, or a filter such as a memory type, time range, metadata
+// filter, or tag
+func (q SearchQuery) Validate() error {
+	if err := q.validateAgents(); err != nil {
+		return err
+	}
+	hasText := q.TextQuery != nil && *q.TextQuery != ""
+	hasVector := len(q.VectorQuery) > 0 || q.EmbedText != ""
+	if !hasText && !hasVector && !q.hasFilters() {
+		return NewValidationError("at least one of text query, vector query, embed text, or a filter is required")
+	}
+	if err := validateMemoryType(q.MemoryType); err != nil {
+		return err
+	}
+	if q.MinImportance != nil {
+		if err := validateImportance("min importance", *q.MinImportance); err != nil {
+			return err
+		}
+	}
+	if q.HybridWeight != nil {
+		if err := validateImportance("hybrid weight", *q.HybridWeight); err != nil {
+			return err
+		}
+		if !hasText || !hasVector {
+			return NewValidationError("hybrid weight requires both a text query and a vector query")
+		}
+	}
+	if q.Highlight && !hasText {
+		return NewValidationError("highlighting requires a text query")
+	}
+	if err := q.validateTimeRanges(); err != nil {
+		return err
+	}
+	if err := q.validateTags(); err != nil {
+		return err
+	}
+	if err := q.validateSort(hasText || hasVector); err != nil {
+		return err
+	}
+	return validateFilters(q.AdvancedFilters)
+}
+
+// validateSort checks the sort field and order of a query. Sorting by
+// relevance needs a text or vector query to score results against.
+func (q SearchQuery) validateSort(scored bool) error {
+	switch q.SortOrder {
+	case "", SortAsc, SortDesc:
+	default:
+		return NewValidationError(fmt.Sprintf("invalid sort order %q", q.SortOrder))
+	}
+	switch q.SortBy {
+	case "":
+		return nil
+	case SortByRelevance:
+		if !scored {
+			return NewValidationError("sorting by relevance requires a text or vector query")
+		}
+		return nil
+	}
+	return validateSortField(q.SortBy)
+}
+
+// validateTags checks the tags of a query and how they are matched
+func (q SearchQuery) validateTags() error {
+	switch q.TagMatchMode {
+	case "", TagMatchAny, TagMatchAll:
+	default:
+		return NewValidationError(fmt.Sprintf("invalid tag match mode %q", q.TagMatchMode))
+	}
+	return validateTags(q.Tags)
+}
+
+// validateTags checks that no tag is blank
+func validateTags(tags []string) error {
+	for _, tag := range tags {
+		if strings.TrimSpace(tag) == "" {
+			return NewValidationError("tags must not be blank")
+		}
+	}
+	return nil
+}
+
+// validateAgents checks that the query is scoped to a single agent or to a
+// list of agents, but not both
+func (q SearchQuery) validateAgents() error {
+	if q.AgentID != "" && len(q.AgentIDs) > 0 {
+		return NewValidationError("agent ID and agent IDs are mutually exclusive")
+	}
+	if q.AgentID == "" && len(q.AgentIDs) == 0 {
+		return NewValidationError("agent ID is required")
+	}
+	for _, agentID := range q.AgentIDs {
+		if agentID == "" {
+			return NewValidationError("agent IDs must not be empty")
+		}
+	}
+	return nil
+}
+
+// validateTimeRanges checks that the created and updated windows of a query
+// are not inverted
+func (q SearchQuery) validateTimeRanges() error {
+	if err := validateTimeRange("created", q.CreatedAfter, q.CreatedBefore); err != nil {
+		return err
+	}
+	return validateTimeRange("updated", q.UpdatedAfter, q.UpdatedBefore)
+}
+
+// validateTimeRange checks that after is not later than before when both are set
+func validateTimeRange(field string, after, before *time.Time) error {
+	if after != nil && before != nil && after.After(*before) {
+		return NewValidationError(fmt.Sprintf("%s after (%s) must not be later than %s before (%s)",
+			field, after.Format(time.RFC3339), field, before.Format(time.RFC3339)))
+	}
+	return nil
+}
+
+// validateFilters checks that each filter names a field and a known operator
+func validateFilters(filters []MetadataFilter) error {
+	for _, filter := range filters {
+		if filter.Field == "" {
+			return NewValidationError("metadata filter field is required")
+		}
+		switch filter.Operator {
+		case FilterOpEq, FilterOpNe, FilterOpIn, FilterOpGt, FilterOpGte,
+			FilterOpLt, FilterOpLte, FilterOpContains, FilterOpExists:
+		default:
+			return NewValidationError(fmt.Sprintf("unknown operator %q in filter on metadata key %q", filter.Operator, filter.Field))
+		}
+	}
+	return nil
+}
+
+// validateSortField checks that a sort field is known and does not need
+// scored results
+func validateSortField(field SortField) error {
+	switch field {
+	case SortByImportance, SortByCreatedAt, SortByUpdatedAt, SortByAccessCount:
+		return nil
+	}
+	return NewValidationError(fmt.Sprintf("unknown sort field %q", field))
+}
+
+// validateImportance checks that an importance value (or another weight) is
+// within [0, 1]
+func validateImportance(field string, importance float64) error {
+	if importance < 0 || importance > 1 || math.IsNaN(importance) {
+		return NewValidationError(fmt.Sprintf("%s must be between 0.0 and 1.0, got %v", field, importance))
+	}
+	return nil
+}
+
+// Validate checks that each cleared field is clearable and not also set,
+// and that the memory type and metadata merge mode are known
+func (p UpdateMemoryParams) Validate() error {
+	if err := validateMemoryType(p.MemoryType); err != nil {
+		return err
+	}
+	for _, field := range p.ClearFields {
+		var set bool
+		switch field {
+		case ClearImportance:
+			set = p.Importance != nil
+		case ClearMetadata:
+			set = p.Metadata != nil
+		default:
+			return NewValidationError(fmt.Sprintf("field %q cannot be cleared", field))
+		}
+		if set {
+			return NewValidationError(fmt.Sprintf("field %q cannot be both set and cleared", field))
+		}
+	}
+	switch p.MetadataMergeMode {
+	case "", MetadataReplace, MetadataMerge:
+	default:
+		return NewValidationError(fmt.Sprintf("invalid metadata merge mode %q", p.MetadataMergeMode))
+	}
+	return nil
+}
+
+// Validate checks that the overrides in RequestOptions are usable
+func (o RequestOptions) Validate() error {
+	if o.Timeout != nil && *o.Timeout <= 0 {
+		return NewValidationError("timeout must be positive")
+	}
+	if o.Retries != nil && *o.Retries < 0 {
+		return NewValidationError("retries must be non-negative")
+	}
+	if o.CacheTTL != nil && *o.CacheTTL <= 0 {
+		return NewValidationError("cache TTL must be positive")
+	}
+	return nil
+}
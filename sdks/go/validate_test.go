@@ -0,0 +1,60 @@
+package agentmem
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSearchQueryValidate(t *testing.T) {
+	text := "coffee"
+	empty := ""
+	memoryType := MemoryTypeEpisodic
+	minImportance := 0.8
+	badImportance := 1.5
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		query   SearchQuery
+		wantErr string
+	}{
+		{name: "text", query: SearchQuery{AgentID: "agent", TextQuery: &text}},
+		{name: "vector", query: SearchQuery{AgentID: "agent", VectorQuery: []float64{0.1}}},
+		{name: "embed text", query: SearchQuery{AgentID: "agent", EmbedText: "coffee"}},
+		{name: "created range only", query: SearchQuery{AgentID: "agent", CreatedAfter: &start, CreatedBefore: &end}},
+		{name: "created after only", query: SearchQuery{AgentID: "agent", CreatedAfter: &start}},
+		{name: "updated range only", query: SearchQuery{AgentID: "agent", UpdatedAfter: &start, UpdatedBefore: &end}},
+		{name: "memory type only", query: SearchQuery{AgentID: "agent", MemoryType: &memoryType}},
+		{name: "min importance only", query: SearchQuery{AgentID: "agent", MinImportance: &minImportance}},
+		{name: "metadata filter", query: SearchQuery{AgentID: "agent", MetadataFilters: map[string]interface{}{"topic": "go"}}},
+		{name: "tags", query: SearchQuery{AgentID: "agent", Tags: []string{"work"}}},
+		{name: "no agent", query: SearchQuery{TextQuery: &text}, wantErr: "agent"},
+		{name: "nothing to match", query: SearchQuery{AgentID: "agent"}, wantErr: "at least one of"},
+		{name: "empty text", query: SearchQuery{AgentID: "agent", TextQuery: &empty}, wantErr: "at least one of"},
+		{name: "inverted created range", query: SearchQuery{AgentID: "agent", CreatedAfter: &end, CreatedBefore: &start}, wantErr: "created"},
+		{name: "inverted updated range", query: SearchQuery{AgentID: "agent", UpdatedAfter: &end, UpdatedBefore: &start}, wantErr: "updated"},
+		{name: "min importance out of range", query: SearchQuery{AgentID: "agent", TextQuery: &text, MinImportance: &badImportance}, wantErr: "min importance"},
+		{name: "highlight without text", query: SearchQuery{AgentID: "agent", Tags: []string{"work"}, Highlight: true}, wantErr: "highlight"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.query.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Validate() = %T %v, want *ValidationError", err, err)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() = %v, want an error mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}